@@ -0,0 +1,114 @@
+package terrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+var errShared = terrors.NewSentinel("shared failure")
+
+func TestBuildersDoNotMutate(t *testing.T) {
+	base := terrors.Wrap(io.EOF, "base").With("a", 1)
+	before := fmt.Sprintf("%+v", base)
+
+	enriched := base.
+		With("b", 2).
+		WithCode(terrors.CodePanic).
+		WithRecovery("try again").
+		WithMismatch(1, 2).
+		WithAttrs(slog.String("attr", "x")).
+		WithSecret("token", "s3cr3t").
+		WithPII("email", "a@b.c").
+		Event(func(e *zerolog.Event) *zerolog.Event { return e.Str("ev", "y") })
+
+	assert.Equal(t, before, fmt.Sprintf("%+v", base))
+	assert.Len(t, base.Fields(), 1)
+	assert.Equal(t, terrors.Code(0), base.Code())
+	assert.Nil(t, base.Recovery())
+
+	assert.Len(t, enriched.Fields(), 8)
+	assert.Equal(t, terrors.CodePanic, enriched.Code())
+	assert.Equal(t, "try again", enriched.Recovery().Suggestion)
+
+	assert.Equal(t, base.Frame(), enriched.Frame())
+	assert.Equal(t, base.Unwrap(), enriched.Unwrap())
+}
+
+func TestBuildersDoNotShareBackingArray(t *testing.T) {
+	base := terrors.New("base").With("a", 1).With("b", 2)
+
+	left := base.With("side", "left")
+	right := base.With("side", "right")
+
+	v, _ := left.Field("side")
+	assert.Equal(t, "left", v)
+	v, _ = right.Field("side")
+	assert.Equal(t, "right", v)
+}
+
+func TestBuildersKeepIdentity(t *testing.T) {
+	err := errShared.Raise()
+	enriched := err.With("id", 7).WithCode(terrors.CodePanic)
+
+	assert.True(t, errors.Is(enriched, errShared))
+	assert.True(t, errors.Is(enriched, terrors.CodePanic))
+	assert.False(t, errors.Is(err, terrors.CodePanic))
+}
+
+var errPlain = terrors.New("plain sentinel")
+
+func TestBuildersKeepIdentityOfNew(t *testing.T) {
+	once := errPlain.With("k", 1)
+	assert.True(t, errors.Is(once, errPlain))
+
+	many := errPlain.With("k", 1).WithCode(terrors.CodePanic).WithRecovery("retry").WithSecret("s", "x")
+	assert.True(t, errors.Is(many, errPlain))
+	assert.True(t, errors.Is(terrors.Wrap(many, "outer"), errPlain))
+
+	step := errPlain.With("a", 1)
+	assert.True(t, errors.Is(step.With("b", 2), step))
+
+	assert.False(t, errors.Is(errPlain, once))
+	assert.False(t, errors.Is(terrors.New("plain sentinel"), errPlain))
+	assert.False(t, errors.Is(errPlain.With("a", 1), errPlain.With("a", 1)))
+}
+
+func TestConcurrentEnrichment(t *testing.T) {
+	shared := terrors.Wrap(io.EOF, "shared").With("base", true)
+
+	var wg sync.WaitGroup
+	results := make([]*terrors.WrapError, 32)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = shared.
+				With("worker", i).
+				WithCode(terrors.Code(i+1)).
+				WithRecoveryf("retry worker %d", i).
+				Event(func(e *zerolog.Event) *zerolog.Event { return e.Int("n", i) })
+			_ = results[i].Error()
+			_ = shared.Fields()
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, shared.Fields(), 1)
+	assert.Equal(t, terrors.Code(0), shared.Code())
+	for i, r := range results {
+		v, ok := r.Field("worker")
+		require.True(t, ok)
+		assert.Equal(t, i, v)
+		assert.Equal(t, terrors.Code(i+1), r.Code())
+		assert.Equal(t, fmt.Sprintf("retry worker %d", i), r.Recovery().Suggestion)
+	}
+}
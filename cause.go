@@ -60,3 +60,18 @@ func FirstCause(err error) (Framer, bool) {
 		}
 	}
 }
+
+// ListAllCauses is the tree-aware version of ListCause: it returns every
+// Framer in the tree, following every branch of joined errors.
+func ListAllCauses(err error) []Framer {
+	var frames []Framer
+
+	Walk(err, func(e error, _ int) bool {
+		if frm, ok := e.(Framer); ok {
+			frames = append(frames, frm)
+		}
+		return true
+	})
+
+	return frames
+}
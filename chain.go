@@ -1,21 +1,155 @@
 package terrors
 
-import "slices"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
+// LinkKind tags an entry of an error chain.
+type LinkKind int
+
+const (
+	// LinkTerror is a *WrapError created by this package.
+	LinkTerror LinkKind = iota
+	// LinkForeign is any other error, e.g. one created by fmt.Errorf.
+	LinkForeign
+	// LinkJoin is an error with multiple branches, e.g. one created by
+	// errors.Join. A chain stops at a join; use Walk to visit the branches.
+	LinkJoin
+)
+
+func (k LinkKind) String() string {
+	switch k {
+	case LinkTerror:
+		return "terror"
+	case LinkForeign:
+		return "foreign"
+	case LinkJoin:
+		return "join"
+	}
+
+	return fmt.Sprintf("LinkKind(%d)", int(k))
+}
+
+// ChainLink is one entry of an error chain.
+type ChainLink struct {
+	Err  error
+	Kind LinkKind
+	// Message is the text contributed by this link alone, without the text
+	// of the errors it wraps.
+	Message string
+}
+
+// GetChain returns err followed by every error it wraps, following the
+// standard Unwrap() error protocol through terrors and foreign wrappers alike.
+// The chain stops at errors that do not unwrap (such as errors.Opaque) and at
+// joined errors.
 func GetChain(err error) []error {
 	errs := []error{}
 	for err != nil {
 		errs = append(errs, err)
-		if we, ok := err.(*wrapError); ok {
-			err = we.err
-		} else {
+		if _, ok := err.(multiUnwrapper); ok {
+			break
+		}
+		kids := unwrapChildren(err)
+		if len(kids) == 0 {
 			break
 		}
+		err = kids[0]
 	}
 
 	return errs
 }
 
+// GetChainLinks is like GetChain but tags every entry with its kind.
+func GetChainLinks(err error) []ChainLink {
+	chain := GetChain(err)
+	links := make([]ChainLink, 0, len(chain))
+
+	for _, e := range chain {
+		switch v := e.(type) {
+		case *WrapError:
+			links = append(links, ChainLink{Err: e, Kind: LinkTerror, Message: v.msg})
+		case multiUnwrapper:
+			links = append(links, ChainLink{Err: e, Kind: LinkJoin, Message: e.Error()})
+		default:
+			links = append(links, ChainLink{Err: e, Kind: LinkForeign, Message: foreignMessage(e)})
+		}
+	}
+
+	return links
+}
+
+// foreignMessage returns the part of a foreign error's message that is not
+// repeated from the error it wraps, e.g. "reading config" for
+// fmt.Errorf("reading config: %w", err), or the whole message if it does not
+// repeat it.
+func foreignMessage(err error) string {
+	msg, _ := ownMessage(err)
+	return msg
+}
+
+// ownMessage is like foreignMessage and also reports whether the message of
+// the wrapped error was cut out. If it was not, the error should be shown as
+// a leaf, since its message already covers the errors below it. The result
+// is empty for wrappers that add nothing, like fmt.Errorf("%w", err).
+func ownMessage(err error) (string, bool) {
+	msg := err.Error()
+
+	kids := unwrapChildren(err)
+	if len(kids) != 1 {
+		return msg, false
+	}
+
+	child := kids[0].Error()
+	i := strings.LastIndex(msg, child)
+	if child == "" || i < 0 {
+		return msg, false
+	}
+
+	own, ok := cutOperand(msg[:i], msg[i+len(child):])
+	if !ok {
+		return msg, false
+	}
+
+	return own, true
+}
+
+// cutOperand joins the text left around an operand cut out of a message,
+// dropping the brackets around the operand and the separator next to it:
+// "failed (" and ")" give "failed", "" and " (while reading)" give
+// "(while reading)". It reports false if the operand sits in the middle of
+// the message, where cutting it would leave text that does not read.
+func cutOperand(before, after string) (string, bool) {
+	for _, pair := range []string{"()", "[]", "{}", `""`, "''"} {
+		if strings.HasSuffix(before, pair[:1]) && strings.HasPrefix(after, pair[1:]) {
+			before, after = before[:len(before)-1], after[1:]
+			break
+		}
+	}
+
+	const seps = ":;,-"
+	switch {
+	case strings.TrimSpace(after) == "":
+		before = strings.TrimRight(before, " ")
+		if before != "" && strings.IndexByte(seps, before[len(before)-1]) >= 0 {
+			before = before[:len(before)-1]
+		}
+		return strings.TrimRight(before, " "), true
+	case strings.TrimSpace(before) == "":
+		after = strings.TrimLeft(after, " ")
+		if after != "" && strings.IndexByte(seps, after[0]) >= 0 {
+			after = after[1:]
+		}
+		return strings.TrimLeft(after, " "), true
+	case strings.HasSuffix(before, ": ") && strings.HasPrefix(after, ": "):
+		return before + after[2:], true
+	}
+
+	return before + after, false
+}
+
 func GetDeepest(err error) error {
 	errs := GetChain(err)
 	if len(errs) == 0 {
@@ -25,7 +159,7 @@ func GetDeepest(err error) error {
 	return errs[len(errs)-1]
 }
 
-func GetDeepestTerror(err error) *wrapError {
+func GetDeepestTerror(err error) *WrapError {
 	errs := GetChain(err)
 
 	if len(errs) == 0 {
@@ -35,7 +169,7 @@ func GetDeepestTerror(err error) *wrapError {
 	slices.Reverse(errs)
 
 	for _, e := range errs {
-		if we, ok := e.(*wrapError); ok {
+		if we, ok := e.(*WrapError); ok {
 			return we
 		}
 	}
@@ -0,0 +1,87 @@
+package terrors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func TestChainFollowsForeignWrappers(t *testing.T) {
+	inner := terrors.Wrap(io.EOF, "read body").WithRecovery("send the body again")
+	foreign := fmt.Errorf("handling request: %w", inner)
+	outer := terrors.Wrap(foreign, "serve")
+
+	assert.Equal(t, []error{outer, foreign, inner, io.EOF}, terrors.GetChain(outer))
+	assert.Equal(t, inner, terrors.GetDeepestTerror(outer))
+	assert.Equal(t, io.EOF, terrors.GetDeepest(outer))
+
+	links := terrors.GetChainLinks(outer)
+	require.Len(t, links, 4)
+	assert.Equal(t, terrors.LinkTerror, links[0].Kind)
+	assert.Equal(t, terrors.LinkForeign, links[1].Kind)
+	assert.Equal(t, "handling request", links[1].Message)
+	assert.Equal(t, terrors.LinkTerror, links[2].Kind)
+	assert.Equal(t, "read body", links[2].Message)
+	assert.Equal(t, terrors.LinkForeign, links[3].Kind)
+
+	ok, info := terrors.IsRecoverable(outer)
+	require.True(t, ok)
+	assert.Equal(t, "send the body again", info.Suggestion)
+
+	full := terrors.FullChainFormatter(outer)
+	assert.Contains(t, full, "- handling request\n")
+	assert.Contains(t, full, "x EOF")
+
+	assert.Contains(t, outer.Error(), " -> handling request -> ")
+}
+
+func TestChainStopsAtOpaque(t *testing.T) {
+	inner := terrors.New("hidden")
+	outer := terrors.Wrap(errors.Opaque(inner), "visible")
+
+	chain := terrors.GetChain(outer)
+	require.Len(t, chain, 2)
+	assert.Nil(t, terrors.GetDeepestTerror(chain[1]))
+	assert.Equal(t, outer, terrors.GetDeepestTerror(outer))
+}
+
+func TestChainForeignMessageAroundCause(t *testing.T) {
+	inner := terrors.New("inner")
+
+	for _, tt := range []struct {
+		format string
+		own    string
+	}{
+		{"%w (while reading)", "(while reading)"},
+		{"failed (%w)", "failed"},
+		{"reading: %w: giving up", "reading: giving up"},
+		{"%w", ""},
+	} {
+		outer := terrors.Wrap(fmt.Errorf(tt.format, inner), "outer")
+
+		links := terrors.GetChainLinks(outer)
+		require.Len(t, links, 3, tt.format)
+		assert.Equal(t, tt.own, links[1].Message, tt.format)
+		assert.Equal(t, 1, strings.Count(outer.Error(), "msg=inner"), tt.format)
+		assert.Equal(t, 1, strings.Count(terrors.FullChainFormatter(outer), "msg=inner"), tt.format)
+
+		foreign := fmt.Errorf(tt.format, io.EOF)
+		b, err := terrors.ToJSON(foreign)
+		require.NoError(t, err)
+		back, err := terrors.FromJSON(b)
+		require.NoError(t, err)
+		assert.Equal(t, foreign.Error(), back.Error(), tt.format)
+	}
+
+	// without the cause's text the wrapper is shown as a leaf
+	outer := terrors.Wrap(fmt.Errorf("in the %w middle", inner), "outer")
+	full := terrors.FullChainFormatter(outer)
+	assert.Equal(t, 1, strings.Count(full, "msg=inner"))
+	assert.Contains(t, full, "x in the ")
+}
@@ -0,0 +1,157 @@
+package terrors
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Code identifies a class of error. Codes are declared once with RegisterCode
+// so they carry a name, a description and default transport mappings.
+//
+// A Code is also an error so it can be used as an errors.Is target: an error
+// matches a code if any terror in its chain carries that code.
+type Code int
+
+// CodeInfo describes a registered Code.
+type CodeInfo struct {
+	Name        string
+	Description string
+	// HTTPStatus is the default HTTP status for the code, or 0 for none.
+	HTTPStatus int
+	// GRPCCode is the default gRPC code for the code, or 0 for none.
+	GRPCCode uint32
+	// Retryable reports whether operations failing with this code may be
+	// retried.
+	Retryable bool
+}
+
+var (
+	codesMu     sync.RWMutex
+	codeInfos   = map[Code]CodeInfo{}
+	codesByName = map[string]Code{}
+)
+
+// RegisterCode declares code with info and returns it, so it can be used to
+// initialize a package level variable. It panics if code is zero, has no
+// name, or collides with a registered code or name.
+func RegisterCode(code Code, info CodeInfo) Code {
+	if code == 0 {
+		panic("terrors: cannot register code 0")
+	}
+	if info.Name == "" {
+		panic(fmt.Sprintf("terrors: code %d registered without a name", int(code)))
+	}
+
+	codesMu.Lock()
+	defer codesMu.Unlock()
+
+	if existing, ok := codeInfos[code]; ok {
+		panic(fmt.Sprintf("terrors: code %d already registered as %s", int(code), existing.Name))
+	}
+	if existing, ok := codesByName[info.Name]; ok {
+		panic(fmt.Sprintf("terrors: code name %s already registered for code %d", info.Name, int(existing)))
+	}
+
+	codeInfos[code] = info
+	codesByName[info.Name] = code
+
+	return code
+}
+
+// LookupCode returns the code registered under name.
+func LookupCode(name string) (Code, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	code, ok := codesByName[name]
+	return code, ok
+}
+
+// RegisteredCodes returns every registered code in ascending order.
+func RegisteredCodes() []Code {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	out := make([]Code, 0, len(codeInfos))
+	for code := range codeInfos {
+		out = append(out, code)
+	}
+	slices.Sort(out)
+
+	return out
+}
+
+// Info returns the registration of c.
+func (c Code) Info() (CodeInfo, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+
+	info, ok := codeInfos[c]
+	return info, ok
+}
+
+// String returns the registered name of c, or its number if c is not
+// registered.
+func (c Code) String() string {
+	if info, ok := c.Info(); ok {
+		return info.Name
+	}
+	return fmt.Sprintf("%d", int(c))
+}
+
+func (c Code) Error() string {
+	return c.String()
+}
+
+// CodeOf returns the code of the outermost terror in err's tree that has one.
+func CodeOf(err error) Code {
+	var code Code
+	Walk(err, func(e error, _ int) bool {
+		if werr, ok := e.(*WrapError); ok && werr.code != 0 {
+			code = werr.code
+			return false
+		}
+		return true
+	})
+
+	return code
+}
+
+// HasCode reports whether any terror in err's chain carries code. It is
+// equivalent to errors.Is(err, code).
+func HasCode(err error, code Code) bool {
+	return errors.Is(err, code)
+}
+
+// Is reports whether target is the Code of e or the Sentinel e was raised
+// from, which makes both usable as errors.Is targets. It also matches the
+// errors e was derived from by builder methods such as With, and the errors
+// passed to Wrapf with %w.
+func (e *WrapError) Is(target error) bool {
+	switch t := target.(type) {
+	case *WrapError:
+		for o := e.origin; o != nil; o = o.origin {
+			if o == t {
+				return true
+			}
+		}
+	case Code:
+		if t != 0 && e.code == t {
+			return true
+		}
+	case *Sentinel:
+		if e.sentinel != nil && e.sentinel == t {
+			return true
+		}
+	}
+
+	for _, w := range e.also {
+		if errors.Is(w, target) {
+			return true
+		}
+	}
+
+	return false
+}
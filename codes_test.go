@@ -0,0 +1,76 @@
+package terrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+var (
+	codeQuotaExceeded = terrors.RegisterCode(9001, terrors.CodeInfo{
+		Name:        "QUOTA_EXCEEDED",
+		Description: "the tenant ran out of quota",
+		HTTPStatus:  429,
+		GRPCCode:    8,
+		Retryable:   true,
+	})
+	codeBadInput = terrors.RegisterCode(9002, terrors.CodeInfo{
+		Name:       "BAD_INPUT",
+		HTTPStatus: 400,
+		GRPCCode:   3,
+	})
+)
+
+func TestCodeRegistry(t *testing.T) {
+	info, ok := codeQuotaExceeded.Info()
+	require.True(t, ok)
+	assert.Equal(t, "QUOTA_EXCEEDED", info.Name)
+	assert.True(t, info.Retryable)
+
+	code, ok := terrors.LookupCode("BAD_INPUT")
+	require.True(t, ok)
+	assert.Equal(t, codeBadInput, code)
+
+	assert.Equal(t, "QUOTA_EXCEEDED", codeQuotaExceeded.String())
+	assert.Equal(t, "77", terrors.Code(77).String())
+
+	assert.Contains(t, terrors.RegisteredCodes(), codeBadInput)
+
+	assert.Panics(t, func() { terrors.RegisterCode(9001, terrors.CodeInfo{Name: "OTHER"}) })
+	assert.Panics(t, func() { terrors.RegisterCode(9003, terrors.CodeInfo{Name: "BAD_INPUT"}) })
+	assert.Panics(t, func() { terrors.RegisterCode(0, terrors.CodeInfo{Name: "ZERO"}) })
+}
+
+func TestCodeMatching(t *testing.T) {
+	inner := terrors.New("over quota").WithCode(codeQuotaExceeded)
+	err := terrors.Wrap(fmt.Errorf("charging: %w", inner), "checkout")
+
+	assert.True(t, errors.Is(err, codeQuotaExceeded))
+	assert.True(t, terrors.HasCode(err, codeQuotaExceeded))
+	assert.False(t, errors.Is(err, codeBadInput))
+	assert.False(t, errors.Is(errors.New("plain"), codeBadInput))
+
+	assert.Equal(t, codeQuotaExceeded, terrors.CodeOf(err))
+	assert.Equal(t, codeBadInput, terrors.CodeOf(terrors.Wrap(inner, "outer").WithCode(codeBadInput)))
+	assert.Equal(t, terrors.Code(0), terrors.CodeOf(errors.New("plain")))
+}
+
+func TestCodeRendering(t *testing.T) {
+	err := terrors.New("over quota").WithCode(codeQuotaExceeded)
+
+	assert.Contains(t, err.Error(), "QUOTA_EXCEEDED")
+	assert.NotContains(t, err.Error(), "9001")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, float64(9001), got["code"])
+	assert.Equal(t, "QUOTA_EXCEEDED", got["code_name"])
+}
@@ -0,0 +1,108 @@
+package terrors
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+type contextFieldsKey struct{}
+
+type contextExtractor struct {
+	name    string
+	extract func(ctx context.Context) (any, bool)
+}
+
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []contextExtractor
+)
+
+// ContextWithFields returns a copy of ctx carrying fields. Every terror
+// created with NewCtx, ErrorfCtx, WrapCtx or WrapfCtx from the returned
+// context gets them stamped on.
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	existing, _ := ctx.Value(contextFieldsKey{}).([]Field)
+
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ContextWith is a shorthand for ContextWithFields with a single field.
+func ContextWith(ctx context.Context, name string, value any) context.Context {
+	return ContextWithFields(ctx, Field{Key: name, Value: value})
+}
+
+// RegisterContextField registers an extractor that pulls a field named name
+// out of any context, e.g. a request ID stored by another package. It is
+// meant to be called during program initialization.
+func RegisterContextField(name string, extract func(ctx context.Context) (any, bool)) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+
+	contextExtractors = append(contextExtractors, contextExtractor{name: name, extract: extract})
+}
+
+// FieldsFromContext returns the fields added to ctx with ContextWithFields
+// followed by the fields of every registered extractor that matched.
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	fields = append([]Field(nil), fields...)
+
+	contextExtractorsMu.RLock()
+	defer contextExtractorsMu.RUnlock()
+
+	for _, ex := range contextExtractors {
+		if v, ok := ex.extract(ctx); ok {
+			fields = append(fields, Field{Key: ex.name, Value: v})
+		}
+	}
+
+	return fields
+}
+
+// NewCtx is like New but stamps the fields carried by ctx.
+func NewCtx(ctx context.Context, text string) *WrapError {
+	return stampContext(ctx, wrapWithCaller(nil, text, 1, false))
+}
+
+// ErrorfCtx is like Errorf but stamps the fields carried by ctx.
+func ErrorfCtx(ctx context.Context, format string, a ...any) *WrapError {
+	msg, wrapped := formatMessage(format, a)
+	return stampContext(ctx, wrapWithCaller(wrapCause(nil, wrapped), msg, 1, false).withTemplate(format, a))
+}
+
+// WrapCtx is like Wrap but stamps the fields carried by ctx.
+func WrapCtx(ctx context.Context, err error, message string) *WrapError {
+	return stampContext(ctx, wrapWithCaller(err, message, 1, false))
+}
+
+// WrapfCtx is like Wrapf but stamps the fields carried by ctx.
+func WrapfCtx(ctx context.Context, err error, format string, a ...any) *WrapError {
+	cause, msg, also := formatWrap(err, format, a)
+	we := wrapWithCaller(cause, msg, 1, false).withTemplate(format, a)
+	we.also = also
+	return stampContext(ctx, we)
+}
+
+// stampContext adds the context fields to we, skipping the ones an error it
+// wraps already carries with the same value.
+func stampContext(ctx context.Context, we *WrapError) *WrapError {
+	for _, f := range FieldsFromContext(ctx) {
+		if we.err != nil {
+			if v, ok := LookupField(we.err, f.Key); ok && reflect.DeepEqual(v, f.Value) {
+				continue
+			}
+		}
+		we.fields = append(we.fields, f)
+	}
+
+	return we
+}
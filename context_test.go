@@ -0,0 +1,59 @@
+package terrors_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+type tenantKey struct{}
+
+func init() {
+	terrors.RegisterContextField("tenant", func(ctx context.Context) (any, bool) {
+		v, ok := ctx.Value(tenantKey{}).(string)
+		return v, ok
+	})
+}
+
+func TestContextFields(t *testing.T) {
+	ctx := terrors.ContextWith(context.Background(), "request_id", "req-1")
+	ctx = terrors.ContextWithFields(ctx, terrors.Field{Key: "trace_id", Value: "tr-9"})
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	assert.Equal(t, []terrors.Field{
+		{Key: "request_id", Value: "req-1"},
+		{Key: "trace_id", Value: "tr-9"},
+		{Key: "tenant", Value: "acme"},
+	}, terrors.FieldsFromContext(ctx))
+
+	inner := terrors.WrapCtx(ctx, io.EOF, "read").With("bytes", 0)
+	assert.Equal(t, []terrors.Field{
+		{Key: "request_id", Value: "req-1"},
+		{Key: "trace_id", Value: "tr-9"},
+		{Key: "tenant", Value: "acme"},
+		{Key: "bytes", Value: 0},
+	}, inner.Fields())
+
+	_, fn, file, _ := inner.Frame().Location()
+	assert.Equal(t, "TestContextFields", fn)
+	assert.Equal(t, "context_test.go", file)
+
+	// fields already carried by the cause are not repeated
+	outer := terrors.WrapfCtx(terrors.ContextWith(ctx, "attempt", 2), inner, "load %s", "config")
+	assert.Equal(t, []terrors.Field{{Key: "attempt", Value: 2}}, outer.Fields())
+
+	assert.Contains(t, outer.Detail(), "attempt")
+	assert.Contains(t, inner.Detail(), "req-1")
+
+	b, err := json.Marshal(terrors.ErrorfCtx(ctx, "failed %d", 1))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"request_id":"req-1"`)
+
+	assert.Equal(t, []terrors.Field{{Key: "tenant", Value: "acme"}}, terrors.NewCtx(context.WithValue(context.Background(), tenantKey{}, "acme"), "x").Fields())
+	assert.Empty(t, terrors.NewCtx(context.Background(), "x").Fields())
+}
@@ -24,7 +24,7 @@ func (s *stringWriter) Write(b []byte) (int, error) {
 	return s.Builder.WriteString(str)
 }
 
-func (e *wrapError) Detail() string {
+func (e *WrapError) Detail() string {
 	srtwrite := &strings.Builder{}
 	w1 := zerolog.New(&stringWriter{srtwrite})
 	pkg, funct, filestr, linestr := e.Frame().Location()
@@ -39,12 +39,21 @@ func (e *wrapError) Detail() string {
 		ed = ed.AnErr("chain", e.err)
 	}
 
-	for _, ev := range e.event {
-		ed = ev(ed)
+	for _, f := range e.FieldsFor(TargetConsole) {
+		ed = ed.Interface(f.Key, f.Value)
+	}
+
+	if args := e.argsFor(TargetConsole); len(args) > 0 {
+		ed = ed.Interface("args", args)
 	}
 
 	ed.Send()
 
+	if stack := e.Stack(); len(stack) > 0 {
+		srtwrite.WriteString("\n\nstack:\n")
+		srtwrite.WriteString(FormatStack(stack))
+	}
+
 	return srtwrite.String()
 }
 
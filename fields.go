@@ -0,0 +1,137 @@
+package terrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
+	"github.com/rs/zerolog"
+)
+
+// Field is a key/value pair attached to an error.
+type Field struct {
+	Key   string
+	Value any
+	// Sensitivity marks the field for redaction. See Redact.
+	Sensitivity Sensitivity
+}
+
+// Fields returns every field attached to e in the order they were attached.
+// Fields attached through Event are listed last; since they can only be read
+// by running them through zerolog, their values are JSON decoded (numbers
+// become float64) and sorted by key.
+func (e *WrapError) Fields() []Field {
+	out := slices.Clone(e.fields)
+
+	evs, err := e.eventFields()
+	if err != nil {
+		return out
+	}
+
+	return append(out, evs...)
+}
+
+// Field returns the value of the field named name attached to e. When a name
+// was attached more than once, the last value wins.
+func (e *WrapError) Field(name string) (any, bool) {
+	fields := e.Fields()
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == name {
+			return fields[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// LookupField returns the value of the field named name from the outermost
+// terror in err's tree that has it.
+func LookupField(err error, name string) (value any, ok bool) {
+	Walk(err, func(e error, _ int) bool {
+		if werr, isTerror := e.(*WrapError); isTerror {
+			value, ok = werr.Field(name)
+		}
+		return !ok
+	})
+
+	return value, ok
+}
+
+// AllFields returns the fields of every terror in err's tree, outermost
+// first. When a name appears more than once, only the outermost is kept.
+func AllFields(err error) []Field {
+	out := []Field{}
+	seen := map[string]int{}
+
+	Walk(err, func(e error, _ int) bool {
+		werr, ok := e.(*WrapError)
+		if !ok {
+			return true
+		}
+
+		local := map[string]bool{}
+		for _, f := range werr.Fields() {
+			idx, dup := seen[f.Key]
+			switch {
+			case !dup:
+				seen[f.Key] = len(out)
+				local[f.Key] = true
+				out = append(out, f)
+			case local[f.Key]:
+				// a later value for the same key on the same error wins
+				out[idx] = f
+			}
+		}
+		return true
+	})
+
+	return out
+}
+
+// eventFields runs the zerolog events attached through Event and returns the
+// fields they produce, sorted by key.
+func (e *WrapError) eventFields() ([]Field, error) {
+	if len(e.event) == 0 {
+		return nil, nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	logger := zerolog.New(buf)
+	ed := logger.Log()
+	for _, ev := range e.event {
+		ed = ev(ed)
+	}
+	ed.Send()
+
+	dat := map[string]any{}
+	if err := json.Unmarshal(buf.Bytes(), &dat); err != nil {
+		return nil, Wrap(err, "decoding error fields")
+	}
+
+	keys := make([]string, 0, len(dat))
+	for k := range dat {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	fields := make([]Field, 0, len(keys))
+	for _, k := range keys {
+		fields = append(fields, Field{Key: k, Value: dat[k]})
+	}
+
+	return fields, nil
+}
+
+// fieldsMap flattens fields into a map, later values winning.
+func fieldsMap(fields []Field) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	m := make(map[string]any, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+
+	return m
+}
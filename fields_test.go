@@ -0,0 +1,71 @@
+package terrors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/walteh/terrors"
+)
+
+func TestFields(t *testing.T) {
+	err := terrors.New("boom").
+		With("user_id", 42).
+		With("tenant", "acme").
+		Event(func(e *zerolog.Event) *zerolog.Event {
+			return e.Str("legacy", "yes").Int("count", 3)
+		}).
+		With("user_id", 43)
+
+	assert.Equal(t, []terrors.Field{
+		{Key: "user_id", Value: 42},
+		{Key: "tenant", Value: "acme"},
+		{Key: "user_id", Value: 43},
+		{Key: "count", Value: float64(3)},
+		{Key: "legacy", Value: "yes"},
+	}, err.Fields())
+
+	v, ok := err.Field("user_id")
+	assert.True(t, ok)
+	assert.Equal(t, 43, v)
+
+	v, ok = err.Field("legacy")
+	assert.True(t, ok)
+	assert.Equal(t, "yes", v)
+
+	_, ok = err.Field("missing")
+	assert.False(t, ok)
+
+	assert.Contains(t, err.Detail(), "tenant")
+}
+
+func TestChainFields(t *testing.T) {
+	inner := terrors.New("inner").With("request_id", "r-1").With("shared", "inner")
+	err := terrors.Wrap(fmt.Errorf("foreign: %w", inner), "outer").With("shared", "outer")
+
+	v, ok := terrors.LookupField(err, "request_id")
+	assert.True(t, ok)
+	assert.Equal(t, "r-1", v)
+
+	v, ok = terrors.LookupField(err, "shared")
+	assert.True(t, ok)
+	assert.Equal(t, "outer", v)
+
+	_, ok = terrors.LookupField(err, "missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, []terrors.Field{
+		{Key: "shared", Value: "outer"},
+		{Key: "request_id", Value: "r-1"},
+	}, terrors.AllFields(err))
+}
+
+func TestMismatchFields(t *testing.T) {
+	err := terrors.Mismatch(1, 2)
+
+	expected, _ := err.Field("expected")
+	actual, _ := err.Field("actual")
+	assert.Equal(t, 1, expected)
+	assert.Equal(t, 2, actual)
+}
@@ -0,0 +1,98 @@
+package terrors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+)
+
+type fingerprintConfig struct {
+	fields          []string
+	foreignMessages bool
+	lines           bool
+}
+
+// FingerprintOption configures Fingerprint.
+type FingerprintOption func(*fingerprintConfig)
+
+// FingerprintFields includes the values of the named fields in the
+// fingerprint, e.g. to group errors per tenant.
+func FingerprintFields(names ...string) FingerprintOption {
+	return func(c *fingerprintConfig) {
+		c.fields = append(c.fields, names...)
+	}
+}
+
+// FingerprintForeignMessages includes the messages of foreign errors in the
+// fingerprint. By default only their type is used, since their messages
+// usually embed dynamic values.
+func FingerprintForeignMessages() FingerprintOption {
+	return func(c *fingerprintConfig) {
+		c.foreignMessages = true
+	}
+}
+
+// FingerprintIgnoreLines leaves line numbers out of the fingerprint so that
+// groups survive unrelated edits to the same functions.
+func FingerprintIgnoreLines() FingerprintOption {
+	return func(c *fingerprintConfig) {
+		c.lines = false
+	}
+}
+
+// Fingerprint returns a stable identity for err, suitable to group and
+// deduplicate identical failures. It hashes the shape of the tree, the frame
+// (package, function and line), code and message template of every terror,
+// and the type of every foreign error. Messages built by Errorf and Wrapf
+// contribute their format string, not the formatted result, so errors that
+// only differ by the values they mention share a fingerprint.
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+
+	cfg := &fingerprintConfig{lines: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	h := sha256.New()
+
+	Walk(err, func(e error, depth int) bool {
+		fmt.Fprintf(h, "%d|", depth)
+
+		switch v := e.(type) {
+		case *WrapError:
+			pkg, function, _, line := v.frame.Location()
+			if !cfg.lines {
+				line = 0
+			}
+
+			template := v.template
+			if template == "" {
+				template = v.msg
+			}
+
+			fmt.Fprintf(h, "terror|%s|%s|%d|%d|%q", pkg, function, line, int(v.code), template)
+
+			for _, f := range v.Fields() {
+				if slices.Contains(cfg.fields, f.Key) {
+					fmt.Fprintf(h, "|%s=%v", f.Key, f.Value)
+				}
+			}
+		case multiUnwrapper:
+			fmt.Fprintf(h, "join|%T", e)
+		default:
+			fmt.Fprintf(h, "foreign|%T", e)
+			if cfg.foreignMessages {
+				fmt.Fprintf(h, "|%q", foreignMessage(e))
+			}
+		}
+
+		h.Write([]byte{'\n'})
+		return true
+	})
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
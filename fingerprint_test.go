@@ -0,0 +1,50 @@
+package terrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/walteh/terrors"
+)
+
+func loadOrder(id int, tenant string) error {
+	cause := fmt.Errorf("row %d: %w", id, errors.New("no rows"))
+	return terrors.Wrapf(cause, "loading order %d", id).With("tenant", tenant).WithCode(404)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := loadOrder(1, "acme")
+	b := loadOrder(2, "globex")
+
+	assert.Len(t, terrors.Fingerprint(a), 32)
+	assert.Equal(t, terrors.Fingerprint(a), terrors.Fingerprint(b))
+
+	assert.NotEqual(t, terrors.Fingerprint(a), terrors.Fingerprint(a, terrors.FingerprintFields("tenant")), "fields are opt-in")
+	assert.NotEqual(t,
+		terrors.Fingerprint(a, terrors.FingerprintFields("tenant")),
+		terrors.Fingerprint(b, terrors.FingerprintFields("tenant")))
+	assert.NotEqual(t,
+		terrors.Fingerprint(a, terrors.FingerprintForeignMessages()),
+		terrors.Fingerprint(b, terrors.FingerprintForeignMessages()))
+
+	// a different call site is a different failure
+	other := terrors.Wrapf(fmt.Errorf("row %d: %w", 1, errors.New("no rows")), "loading order %d", 1).With("tenant", "acme").WithCode(404)
+	assert.NotEqual(t, terrors.Fingerprint(a), terrors.Fingerprint(other))
+
+	// so is a different code
+	assert.NotEqual(t, terrors.Fingerprint(terrors.New("x")), terrors.Fingerprint(terrors.New("x").WithCode(1)))
+
+	assert.Equal(t, "", terrors.Fingerprint(nil))
+}
+
+func TestFingerprintIgnoreLines(t *testing.T) {
+	first := terrors.Errorf("user %s missing", "a")
+	second := terrors.Errorf("user %s missing", "b")
+
+	assert.NotEqual(t, terrors.Fingerprint(first), terrors.Fingerprint(second))
+	assert.Equal(t,
+		terrors.Fingerprint(first, terrors.FingerprintIgnoreLines()),
+		terrors.Fingerprint(second, terrors.FingerprintIgnoreLines()))
+}
@@ -0,0 +1,77 @@
+package terrors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+var (
+	_ fmt.Formatter    = (*WrapError)(nil)
+	_ fmt.GoStringer   = (*WrapError)(nil)
+	_ errors.Formatter = (*WrapError)(nil)
+)
+
+// Format implements fmt.Formatter:
+//
+//	%s, %v  the one line chain, same as Error
+//	%+v     the full chain with frames and fields, like FullChainFormatter
+//	%#v     a Go-syntax representation, same as GoString
+//	%q      the one line chain, quoted
+func (e *WrapError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			_, _ = io.WriteString(s, strings.TrimSpace(RenderChain(DefaultRenderer(), e)))
+		case s.Flag('#'):
+			_, _ = io.WriteString(s, e.GoString())
+		default:
+			_, _ = io.WriteString(s, e.Error())
+		}
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*terrors.WrapError=%s)", verb, e.Error())
+	}
+}
+
+// GoString implements fmt.GoStringer. Fields are redacted for TargetConsole
+// and the recovery shows only its suggestion and frame, since its state may
+// hold anything.
+func (e *WrapError) GoString() string {
+	recovery := "nil"
+	if e.recovery != nil {
+		recovery = fmt.Sprintf("{suggestion:%q, frame:%q}", e.recovery.Suggestion, goFrame(e.recovery.Frame))
+	}
+
+	return fmt.Sprintf("&terrors.WrapError{msg:%q, code:%d, frame:%q, fields:%#v, recovery:%s, err:%#v}",
+		e.msg, int(e.code), goFrame(e.frame), e.FieldsFor(TargetConsole), recovery, e.err)
+}
+
+func goFrame(f Frame) string {
+	pkg, function, file, line := f.Location()
+	return fmt.Sprintf("%s.%s %s:%d", pkg, function, file, line)
+}
+
+// FormatError implements errors.Formatter from go-faster/errors: it prints
+// the message and, in detail mode, the frame and fields, then returns the
+// wrapped error so the printer continues down the chain.
+func (e *WrapError) FormatError(p errors.Printer) (next error) {
+	p.Print(e.msg)
+
+	if p.Detail() {
+		pkg, function, file, line := e.frame.Location()
+		p.Printf("%s.%s\n    %s:%d", pkg, function, file, line)
+
+		for _, f := range e.FieldsFor(TargetConsole) {
+			p.Printf("\n    %s = %v", f.Key, f.Value)
+		}
+	}
+
+	return e.err
+}
@@ -0,0 +1,55 @@
+package terrors_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/walteh/terrors"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	err := terrors.Wrap(io.EOF, "read body").With("bytes", 12).WithSecret("token", "hunter2")
+
+	assert.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+	assert.Equal(t, fmt.Sprintf("%q", err.Error()), fmt.Sprintf("%q", err))
+	assert.Equal(t, "%!d(*terrors.WrapError="+err.Error()+")", fmt.Sprintf("%d", err))
+
+	detailed := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(detailed, "- ERROR[msg=read body]"), detailed)
+	assert.Contains(t, detailed, "function = TestFormatVerbs")
+	assert.Contains(t, detailed, "bytes    = 12")
+	assert.True(t, strings.HasSuffix(detailed, "x EOF"), detailed)
+
+	gostr := fmt.Sprintf("%#v", err)
+	assert.True(t, strings.HasPrefix(gostr, `&terrors.WrapError{msg:"read body", code:0, frame:"walteh/terrors_test.TestFormatVerbs fmt_test.go:`), gostr)
+	assert.Contains(t, gostr, `fields:[]terrors.Field{terrors.Field{Key:"bytes", Value:12, Sensitivity:0}, terrors.Field{Key:"token", Value:"[REDACTED]", Sensitivity:3}}`)
+	assert.NotContains(t, gostr, "hunter2")
+	assert.Contains(t, gostr, `recovery:nil`)
+	assert.Contains(t, gostr, `err:&errors.errorString{s:"EOF"}`)
+
+	recoverable := err.WithRecovery("log in again", "session-secret")
+	gostr = fmt.Sprintf("%#v", recoverable)
+	assert.Contains(t, gostr, `recovery:{suggestion:"log in again", frame:"walteh/terrors_test.TestFormatVerbs fmt_test.go:`)
+	assert.NotContains(t, gostr, "session-secret")
+}
+
+// goFasterAdapter prints through the go-faster/errors Formatter machinery.
+type goFasterAdapter struct{ errors.Formatter }
+
+func (a goFasterAdapter) Format(s fmt.State, verb rune) { errors.FormatError(a.Formatter, s, verb) }
+
+func TestFormatError(t *testing.T) {
+	err := terrors.Wrap(terrors.New("inner").With("k", "v"), "outer")
+
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%v", goFasterAdapter{err}))
+
+	detailed := fmt.Sprintf("%+v", goFasterAdapter{err})
+	assert.Contains(t, detailed, "outer:\n    walteh/terrors_test.TestFormatError")
+	assert.Contains(t, detailed, "k = v")
+	assert.Contains(t, detailed, "- inner:")
+}
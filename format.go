@@ -20,22 +20,26 @@ func FormatCallerFromFrame(frm Frame) string {
 	pkg, _, filestr, linestr := frm.Location()
 	return FormatCaller(pkg, filestr, linestr)
 }
+
+// FormatCaller renders a source location with the default Renderer.
 func FormatCaller(pkg, path string, number int) string {
-	pkgd := ColorBrackets("pkg", color.New(color.FgHiGreen).Sprint(pkg))
-	pathd := ColorBrackets("file", fmt.Sprintf("%s:%s", color.New(color.Bold).Sprint(FileNameOfPath(path)), color.New(color.FgHiRed, color.Bold).Sprintf("%d", number)))
-	return fmt.Sprintf("%s%s", pkgd, pathd)
+	return DefaultRenderer().Caller(pkg, FileNameOfPath(path), number)
 }
 
+// ColorBrackets renders a labelled value with ANSI colors, as used by
+// ANSIRenderer.
 func ColorBrackets(label string, value string) string {
 	closeBracket := color.New(color.Faint, color.FgHiCyan).Sprint("]")
 	openBracket := color.New(color.Faint, color.FgHiCyan).Sprint("[")
 	return fmt.Sprintf("%s%s%s%s%s", openBracket, color.New(color.Faint, color.FgHiMagenta).Sprint(label), color.New(color.Faint, color.FgBlack).Sprint("="), value, closeBracket)
 }
 
-func ColorCode(code int) string {
+// ColorCode renders code with ANSI colors using its registered name when
+// there is one, as used by ANSIRenderer.
+func ColorCode(code Code) string {
 	openBracket := color.New(color.Faint, color.FgHiRed).Sprint("{")
 	closeBracket := color.New(color.Faint, color.FgHiRed).Sprint("}")
-	return fmt.Sprintf("%s%s%s%s%s", openBracket, color.New(color.Faint, color.FgHiBlack).Sprint("code"), color.New(color.Faint, color.FgBlack).Sprint("="), color.New(color.FgHiRed, color.Bold).Sprint(code), closeBracket)
+	return fmt.Sprintf("%s%s%s%s%s", openBracket, color.New(color.Faint, color.FgHiBlack).Sprint("code"), color.New(color.Faint, color.FgBlack).Sprint("="), color.New(color.FgHiRed, color.Bold).Sprint(code.String()), closeBracket)
 }
 
 func ExtractErrorDetail(err error) string {
@@ -47,14 +51,26 @@ func ExtractErrorDetail(err error) string {
 }
 
 func FormatErrorCaller(err error, name string, verbose bool) string {
+	return FormatErrorCallerWith(DefaultRenderer(), err, name, verbose)
+}
+
+// FormatErrorCallerWith is like FormatErrorCaller but renders with r.
+func FormatErrorCallerWith(r Renderer, err error, name string, verbose bool) string {
 	// caller := ""
 	dets := ""
 	var errstr string
 	if frm, ok := Cause2(err); ok {
-		if verbose {
+		werr, isTerror := frm.(*WrapError)
+		switch {
+		case verbose && isTerror:
+			errstr = renderInline(r, werr.messageWith(r), werr.err)
+			dets = frm.Detail()
+		case verbose:
 			errstr = frm.Simple()
 			dets = frm.Detail()
-		} else {
+		case isTerror:
+			errstr = werr.ErrorWith(r)
+		default:
 			errstr = frm.Error()
 		}
 	} else {
@@ -73,52 +89,17 @@ func FormatErrorCaller(err error, name string, verbose bool) string {
 		name = "[" + name + "] - "
 	}
 
-	return fmt.Sprintf("%s%s%s", name, color.New(color.FgRed).Sprint(errstr), dets)
+	return fmt.Sprintf("%s%s%s", name, r.Emphasis(errstr), dets)
 }
 
+// InlineChainFormatter renders self followed by the chain below it on one
+// line with the default Renderer.
 func InlineChainFormatter(self func() string, kid error) string {
-
-	if kid == nil {
-		slf := self()
-		if !strings.Contains(slf, "❌") {
-			return "❌ " + slf
-		}
-		return slf
-	}
-
-	errd := kid.Error()
-
-	arrow := "👉"
-
-	if !strings.Contains(errd, arrow) && !strings.HasPrefix(errd, "❌") {
-		arrow += " ❌"
-	}
-
-	return fmt.Sprintf("%s %s %s", self(), arrow, errd)
+	return renderInline(DefaultRenderer(), self(), kid)
 }
 
+// FullChainFormatter renders the multi-line view of kid with the default
+// Renderer.
 func FullChainFormatter(kid error) string {
-
-	chain := GetChain(kid)
-
-	wrk := "\n\n"
-
-	for i, err := range chain {
-		arrow := "👇"
-		if len(chain)-1 == i {
-			arrow = "❌"
-		}
-		wrk += arrow + " "
-		switch v := err.(type) {
-		case *wrapError:
-			wrk += v.DetailedSelf()
-		default:
-			wrk += fmt.Sprintf("%s\n\n", v.Error())
-		}
-	}
-
-	wrk += "\n\n"
-
-	return wrk
-
+	return RenderChain(DefaultRenderer(), kid)
 }
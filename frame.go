@@ -15,6 +15,38 @@ type Frame struct {
 	// and possibly a PC for skipPleaseUseCallersFrames. See:
 	// https://go.googlesource.com/go/+/032678e0fb/src/runtime/extern.go#169
 	frames [3]uintptr
+
+	// loc holds an already resolved location, used for frames that were not
+	// captured by Caller (e.g. entries of a full stack).
+	loc *location
+}
+
+type location struct {
+	pkg, function, file string
+	line                int
+}
+
+// RemoteFrame is a caller location that was resolved elsewhere, typically in
+// another process, and carried over as plain data.
+type RemoteFrame struct {
+	Package  string
+	Function string
+	File     string
+	Line     int
+}
+
+// Frame returns a Frame whose Location reports r.
+func (r RemoteFrame) Frame() Frame {
+	return Frame{loc: &location{pkg: r.Package, function: r.Function, file: r.File, line: r.Line}}
+}
+
+func (r RemoteFrame) Location() (pkg, function, file string, line int) {
+	return r.Package, r.Function, r.File, r.Line
+}
+
+func resolvedFrame(fr runtime.Frame) Frame {
+	pkg, function := GetPackageAndFuncFromFuncName(fr.Function)
+	return Frame{loc: &location{pkg: pkg, function: function, file: FileNameOfPath(fr.File), line: fr.Line}}
 }
 
 // Caller returns a Frame that describes a frame on the caller's stack.
@@ -30,6 +62,9 @@ func Caller(skip int) Frame {
 //
 // The returned function may be "" even if file and line are not.
 func (f Frame) Location() (pkg, function, file string, line int) {
+	if f.loc != nil {
+		return f.loc.pkg, f.loc.function, f.loc.file, f.loc.line
+	}
 	frames := runtime.CallersFrames(f.frames[:])
 	if _, ok := frames.Next(); !ok {
 		return "", "", "", 0
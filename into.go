@@ -1,6 +1,7 @@
 package terrors
 
 import (
+	"cmp"
 	"errors"
 	"slices"
 )
@@ -13,31 +14,178 @@ func Into[T error](err error) (val T, ok bool) {
 	return val, ok
 }
 
+// IntoAll returns every error in err's tree that matches T, in Walk order,
+// including the branches of joined errors.
+func IntoAll[T error](err error) []T {
+	var out []T
+	Walk(err, func(e error, _ int) bool {
+		if v, ok := as[T](e); ok {
+			out = append(out, v)
+		}
+		return true
+	})
+
+	return out
+}
+
+// IntoFunc returns the first error in err's tree that matches T and
+// satisfies pred.
+func IntoFunc[T error](err error, pred func(T) bool) (val T, ok bool) {
+	Walk(err, func(e error, _ int) bool {
+		if v, match := as[T](e); match && pred(v) {
+			val, ok = v, true
+			return false
+		}
+		return true
+	})
+
+	return val, ok
+}
+
+// Has reports whether any error in err's tree matches T.
+func Has[T error](err error) bool {
+	_, ok := IntoFunc(err, func(T) bool { return true })
+	return ok
+}
+
+// MatchCase is a case of Match, built with Case.
+type MatchCase interface {
+	match(err error) bool
+}
+
+type matchCase[T error] func(T)
+
+func (c matchCase[T]) match(err error) bool {
+	v, ok := as[T](err)
+	if ok {
+		c(v)
+	}
+	return ok
+}
+
+// Case returns a MatchCase running fn for errors that match T.
+func Case[T error](fn func(T)) MatchCase {
+	return matchCase[T](fn)
+}
+
+// Match walks err's tree and runs the first case matching the first error it
+// can, like a type switch over the chain members. It reports whether a case
+// ran.
+//
+//	terrors.Match(err,
+//		terrors.Case(func(e *fs.PathError) { ... }),
+//		terrors.Case(func(e *terrors.WrapError) { ... }),
+//	)
+func Match(err error, cases ...MatchCase) bool {
+	matched := false
+	Walk(err, func(e error, _ int) bool {
+		for _, c := range cases {
+			if c.match(e) {
+				matched = true
+				return false
+			}
+		}
+		return true
+	})
+
+	return matched
+}
+
+// as reports whether err itself, without unwrapping, matches T, either
+// directly or through its As method.
+func as[T error](err error) (T, bool) {
+	if v, ok := err.(T); ok {
+		return v, true
+	}
+
+	var v T
+	if x, ok := err.(interface{ As(any) bool }); ok && x.As(&v) {
+		return v, true
+	}
+
+	return v, false
+}
+
+// RecoveryInfo describes a recoverable error in a chain.
 type RecoveryInfo struct {
 	DeepestSimpleErrorMessage string
 	Suggestion                string
+	// State is the state passed to WithRecovery.
+	State []any
+	// Frame is where the recovery was attached to Err.
+	Frame Frame
+	// Err is the error the recovery was attached to.
+	Err *WrapError
+	// Depth is the depth of Err in the tree, as reported by Walk.
+	Depth int
 }
 
+// IsRecoverable reports whether err's tree holds a recoverable error and
+// describes the deepest one, across every branch of joined errors.
 func IsRecoverable(err error) (bool, *RecoveryInfo) {
-	chain := GetChain(err)
-
-	// we want to get the deepest recoverable error in the chain
-	slices.Reverse(chain)
-
-	for _, e := range chain {
-		if werr, ok := e.(*wrapError); ok {
-			if werr.recovery != nil {
-				msg := werr.msg
-				if werr.err != nil {
-					msg += ": " + werr.err.Error()
-				}
-				return true, &RecoveryInfo{
-					DeepestSimpleErrorMessage: msg,
-					Suggestion:                werr.recovery.Suggestion,
-				}
+	var found *RecoveryInfo
+	for _, info := range AllRecoverable(err) {
+		if found == nil || info.Depth > found.Depth {
+			found = info
+		}
+	}
+
+	return found != nil, found
+}
+
+// AllRecoverable describes every recoverable error in err's tree, in Walk
+// order (outermost first).
+func AllRecoverable(err error) []*RecoveryInfo {
+	var out []*RecoveryInfo
+
+	Walk(err, func(e error, depth int) bool {
+		if werr, ok := e.(*WrapError); ok && werr.recovery != nil {
+			out = append(out, newRecoveryInfo(werr, depth))
+		}
+		return true
+	})
+
+	return out
+}
+
+func newRecoveryInfo(werr *WrapError, depth int) *RecoveryInfo {
+	msg := werr.msg
+	if werr.err != nil {
+		msg += ": " + werr.err.Error()
+	}
+
+	return &RecoveryInfo{
+		DeepestSimpleErrorMessage: msg,
+		Suggestion:                werr.recovery.Suggestion,
+		State:                     werr.recovery.State,
+		Frame:                     werr.recovery.Frame,
+		Err:                       werr,
+		Depth:                     depth,
+	}
+}
+
+// WithRecoveryState attaches a recovery suggestion with a typed state to err,
+// to be read back with RecoveryState.
+func WithRecoveryState[T any](err *WrapError, suggestion string, state T) *WrapError {
+	return err.withRecovery(Caller(1), suggestion, []any{state})
+}
+
+// RecoveryState returns the first recovery state of type T in err's tree,
+// looking at the deepest recoverable errors first.
+func RecoveryState[T any](err error) (T, bool) {
+	infos := AllRecoverable(err)
+	slices.SortStableFunc(infos, func(a, b *RecoveryInfo) int {
+		return cmp.Compare(b.Depth, a.Depth)
+	})
+
+	for _, info := range infos {
+		for _, s := range info.State {
+			if v, ok := s.(T); ok {
+				return v, true
 			}
 		}
 	}
 
-	return false, nil
+	var zero T
+	return zero, false
 }
@@ -0,0 +1,74 @@
+package terrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func intoTree() error {
+	left := terrors.Wrap(&fs.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist}, "left")
+	right := fmt.Errorf("right: %w", &fs.PathError{Op: "stat", Path: "b", Err: fs.ErrPermission})
+	return terrors.Wrap(errors.Join(left, right), "top")
+}
+
+func TestIntoAll(t *testing.T) {
+	err := intoTree()
+
+	paths := terrors.IntoAll[*fs.PathError](err)
+	require.Len(t, paths, 2)
+	assert.Equal(t, "a", paths[0].Path)
+	assert.Equal(t, "b", paths[1].Path)
+
+	werrs := terrors.IntoAll[*terrors.WrapError](err)
+	require.Len(t, werrs, 2)
+	assert.Equal(t, []any{"top"}, werrs[0].Info())
+	assert.Equal(t, []any{"left"}, werrs[1].Info())
+
+	assert.Empty(t, terrors.IntoAll[*fs.PathError](io.EOF))
+	assert.Empty(t, terrors.IntoAll[*fs.PathError](nil))
+}
+
+func TestIntoFunc(t *testing.T) {
+	err := intoTree()
+
+	got, ok := terrors.IntoFunc(err, func(e *fs.PathError) bool { return e.Op == "stat" })
+	require.True(t, ok)
+	assert.Equal(t, "b", got.Path)
+
+	_, ok = terrors.IntoFunc(err, func(e *fs.PathError) bool { return e.Op == "write" })
+	assert.False(t, ok)
+}
+
+func TestHas(t *testing.T) {
+	assert.True(t, terrors.Has[*fs.PathError](intoTree()))
+	assert.False(t, terrors.Has[*fs.PathError](io.EOF))
+
+	sentinel := terrors.NewSentinel("gone")
+	assert.True(t, terrors.Has[*terrors.Sentinel](terrors.Wrap(sentinel.Raise(), "outer")))
+}
+
+func TestMatch(t *testing.T) {
+	var got []string
+	cases := []terrors.MatchCase{
+		terrors.Case(func(e *fs.PathError) { got = append(got, "path "+e.Path) }),
+		terrors.Case(func(e *terrors.WrapError) { got = append(got, fmt.Sprint("terror ", e.Info()[0])) }),
+	}
+
+	assert.True(t, terrors.Match(intoTree(), cases...))
+	assert.Equal(t, []string{"terror top"}, got)
+
+	got = nil
+	assert.True(t, terrors.Match(fmt.Errorf("x: %w", &fs.PathError{Path: "c", Err: io.EOF}), cases...))
+	assert.Equal(t, []string{"path c"}, got)
+
+	got = nil
+	assert.False(t, terrors.Match(io.EOF, cases...))
+	assert.Empty(t, got)
+}
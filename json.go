@@ -0,0 +1,352 @@
+package terrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// jsonError is the stable JSON schema of a serialized error chain.
+type jsonError struct {
+	Message  string         `json:"message"`
+	Template string         `json:"template,omitempty"`
+	Args     []any          `json:"args,omitempty"`
+	Code     Code           `json:"code,omitempty"`
+	CodeName string         `json:"code_name,omitempty"`
+	Caller   *jsonFrame     `json:"caller,omitempty"`
+	Stack    []jsonFrame    `json:"stack,omitempty"`
+	Fields   map[string]any `json:"fields,omitempty"`
+	Recovery *jsonRecovery  `json:"recovery,omitempty"`
+	Retry    *jsonRetry     `json:"retry,omitempty"`
+	Foreign  bool           `json:"foreign,omitempty"`
+	// OwnMessage reports that Message of a foreign error leaves out the
+	// messages of its causes, which are appended again when decoding.
+	OwnMessage bool         `json:"own_message,omitempty"`
+	Cause      *jsonError   `json:"cause,omitempty"`
+	Causes     []*jsonError `json:"causes,omitempty"`
+}
+
+type jsonFrame struct {
+	Package  string `json:"package"`
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+type jsonRecovery struct {
+	Suggestion string `json:"suggestion"`
+}
+
+type jsonRetry struct {
+	Retryability string `json:"retryability,omitempty"`
+	AfterMillis  int64  `json:"after_ms,omitempty"`
+	MaxAttempts  int    `json:"max_attempts,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, serializing the error and its whole
+// cause chain. Fields are redacted for TargetLog.
+func (e *WrapError) MarshalJSON() ([]byte, error) {
+	je, err := toJSONError(e, TargetLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(je)
+}
+
+// ToJSON serializes any error and its cause chain using the same schema as
+// (*WrapError).MarshalJSON. Errors not created by this package are marked
+// as foreign.
+func ToJSON(err error) ([]byte, error) {
+	return ToJSONFor(err, TargetLog)
+}
+
+// ToJSONFor is like ToJSON but redacts fields for target. For TargetClient
+// the stacks and the messages of foreign errors are left out too, since
+// either may reveal internals.
+func ToJSONFor(err error, target Target) ([]byte, error) {
+	if err == nil {
+		return []byte("null"), nil
+	}
+
+	je, jerr := toJSONError(err, target)
+	if jerr != nil {
+		return nil, jerr
+	}
+
+	return json.Marshal(je)
+}
+
+func toJSONError(err error, target Target) (*jsonError, error) {
+	if _, ok := err.(*WrapError); !ok && target == TargetClient {
+		if _, ok := err.(multiUnwrapper); !ok {
+			// Foreign links are skipped for clients, keeping only their cause.
+			kids := unwrapChildren(err)
+			if len(kids) == 0 {
+				return nil, nil
+			}
+			return toJSONError(kids[0], target)
+		}
+	}
+
+	je := &jsonError{}
+
+	if we, ok := err.(*WrapError); ok {
+		je.Message = we.msg
+		je.Template = we.template
+		je.Args = jsonArgs(we.argsFor(target))
+		je.Code = we.code
+		if info, ok := we.code.Info(); ok {
+			je.CodeName = info.Name
+		}
+
+		if frm, ok := frameToJSON(we.frame); ok {
+			je.Caller = &frm
+		}
+
+		if target != TargetClient {
+			for _, f := range we.Stack() {
+				if frm, ok := frameToJSON(f); ok {
+					je.Stack = append(je.Stack, frm)
+				}
+			}
+		}
+
+		je.Fields = fieldsMap(we.FieldsFor(target))
+
+		if we.recovery != nil {
+			je.Recovery = &jsonRecovery{Suggestion: we.recovery.Suggestion}
+		}
+
+		if we.retry != (RetryHint{}) {
+			je.Retry = &jsonRetry{
+				AfterMillis: we.retry.After.Milliseconds(),
+				MaxAttempts: we.retry.MaxAttempts,
+			}
+			if we.retry.Retryability != RetryUnspecified {
+				je.Retry.Retryability = we.retry.Retryability.String()
+			}
+		}
+	} else {
+		je.Message = err.Error()
+		je.Foreign = true
+		// only keep the own message if decoding can rebuild the original
+		if own, ok := ownMessage(err); ok && joinOwn(own, unwrapChildren(err)[0].Error()) == je.Message {
+			je.Message, je.OwnMessage = own, true
+		}
+		if isJoinMessage(err) || target == TargetClient {
+			je.Message, je.OwnMessage = "", true
+		}
+	}
+
+	kids := unwrapChildren(err)
+
+	if _, ok := err.(multiUnwrapper); ok {
+		for _, kid := range kids {
+			kj, kerr := toJSONError(kid, target)
+			if kerr != nil {
+				return nil, kerr
+			}
+			if kj != nil {
+				je.Causes = append(je.Causes, kj)
+			}
+		}
+		return je, nil
+	}
+
+	if len(kids) > 0 {
+		kj, kerr := toJSONError(kids[0], target)
+		if kerr != nil {
+			return nil, kerr
+		}
+		je.Cause = kj
+	}
+
+	return je, nil
+}
+
+// jsonArgs replaces the arguments JSON cannot encode, such as funcs and
+// channels, with their fmt.Sprint text.
+func jsonArgs(args []any) []any {
+	for i, a := range args {
+		if _, err := json.Marshal(a); err != nil {
+			args[i] = fmt.Sprint(a)
+		}
+	}
+	return args
+}
+
+func frameToJSON(f Frame) (jsonFrame, bool) {
+	pkg, function, file, line := f.Location()
+	if pkg == "" && file == "" {
+		return jsonFrame{}, false
+	}
+
+	return jsonFrame{Package: pkg, Function: function, File: file, Line: line}, true
+}
+
+// isJoinMessage reports whether err is a multi-error whose message is just
+// the messages of its branches, one per line, as built by errors.Join.
+func isJoinMessage(err error) bool {
+	if _, ok := err.(multiUnwrapper); !ok {
+		return false
+	}
+
+	kids := unwrapChildren(err)
+	msgs := make([]string, 0, len(kids))
+	for _, kid := range kids {
+		msgs = append(msgs, kid.Error())
+	}
+
+	return err.Error() == strings.Join(msgs, "\n")
+}
+
+// remoteError is a decoded foreign error. It keeps the original message and
+// the decoded cause.
+type remoteError struct {
+	msg   string
+	own   bool
+	cause error
+}
+
+func (e *remoteError) Error() string {
+	if e.own && e.cause != nil {
+		return joinOwn(e.msg, e.cause.Error())
+	}
+	return e.msg
+}
+
+// joinOwn rebuilds a foreign message from its own part and its cause's.
+func joinOwn(own, cause string) string {
+	if own == "" {
+		return cause
+	}
+	return own + ": " + cause
+}
+
+func (e *remoteError) Unwrap() error { return e.cause }
+
+// remoteJoinError is a decoded foreign error with multiple branches.
+type remoteJoinError struct {
+	msg    string
+	own    bool
+	causes []error
+}
+
+func (e *remoteJoinError) Error() string {
+	if !e.own {
+		return e.msg
+	}
+
+	msgs := make([]string, 0, len(e.causes))
+	for _, c := range e.causes {
+		msgs = append(msgs, c.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (e *remoteJoinError) Unwrap() []error { return e.causes }
+
+// UnmarshalJSON implements json.Unmarshaler, rebuilding an error serialized
+// by MarshalJSON including its code, recovery, fields, caller locations and
+// cause chain. Foreign and joined errors cannot be decoded into a WrapError;
+// use FromJSON for those.
+func (e *WrapError) UnmarshalJSON(b []byte) error {
+	je := &jsonError{}
+	if err := json.Unmarshal(b, je); err != nil {
+		return err
+	}
+
+	if je.Foreign {
+		return Errorf("cannot decode foreign error %q into a terror", je.Message)
+	}
+
+	we, ok := fromJSONError(je).(*WrapError)
+	if !ok {
+		return Errorf("cannot decode joined error %q into a terror", je.Message)
+	}
+
+	*e = *we
+
+	return nil
+}
+
+// FromJSON rebuilds an error serialized by ToJSON or MarshalJSON. Terrors are
+// decoded as *WrapError with RemoteFrame locations; foreign errors keep their
+// message and position in the chain.
+func FromJSON(b []byte) (error, error) {
+	if string(bytes.TrimSpace(b)) == "null" {
+		return nil, nil
+	}
+
+	je := &jsonError{}
+	if err := json.Unmarshal(b, je); err != nil {
+		return nil, Wrap(err, "decoding error json")
+	}
+
+	return fromJSONError(je), nil
+}
+
+func fromJSONError(je *jsonError) error {
+	if len(je.Causes) > 0 {
+		causes := make([]error, 0, len(je.Causes))
+		for _, c := range je.Causes {
+			causes = append(causes, fromJSONError(c))
+		}
+		return &remoteJoinError{msg: je.Message, own: je.OwnMessage, causes: causes}
+	}
+
+	var cause error
+	if je.Cause != nil {
+		cause = fromJSONError(je.Cause)
+	}
+
+	if je.Foreign {
+		return &remoteError{msg: je.Message, own: je.OwnMessage, cause: cause}
+	}
+
+	we := &WrapError{msg: je.Message, template: je.Template, args: je.Args, err: cause, code: je.Code}
+
+	if je.Caller != nil {
+		we.frame = je.Caller.remote().Frame()
+	}
+
+	for _, f := range je.Stack {
+		we.remoteStack = append(we.remoteStack, f.remote().Frame())
+	}
+
+	keys := make([]string, 0, len(je.Fields))
+	for k := range je.Fields {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		we.fields = append(we.fields, Field{Key: k, Value: je.Fields[k]})
+	}
+
+	if je.Recovery != nil {
+		we.recovery = &Recovery{Suggestion: je.Recovery.Suggestion, Frame: we.frame}
+	}
+
+	if je.Retry != nil {
+		we.retry = RetryHint{
+			After:       time.Duration(je.Retry.AfterMillis) * time.Millisecond,
+			MaxAttempts: je.Retry.MaxAttempts,
+		}
+		switch je.Retry.Retryability {
+		case RetryAllowed.String():
+			we.retry.Retryability = RetryAllowed
+		case RetryPermanent.String():
+			we.retry.Retryability = RetryPermanent
+		}
+	}
+
+	return we
+}
+
+func (f jsonFrame) remote() RemoteFrame {
+	return RemoteFrame{Package: f.Package, Function: f.Function, File: f.File, Line: f.Line}
+}
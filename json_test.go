@@ -0,0 +1,169 @@
+package terrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	root := errors.New("connection refused")
+	inner := terrors.Wrap(root, "dial").WithCode(14).With("host", "db.internal").WithRecovery("check the database")
+	outer := terrors.Wrap(fmt.Errorf("loading user: %w", inner), "get user").With("user_id", 42)
+
+	b, err := json.Marshal(outer)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	assert.Equal(t, "get user", got["message"])
+	assert.Equal(t, map[string]any{"user_id": float64(42)}, got["fields"])
+
+	caller := got["caller"].(map[string]any)
+	assert.Equal(t, "walteh/terrors_test", caller["package"])
+	assert.Equal(t, "TestMarshalJSON", caller["function"])
+	assert.Equal(t, "json_test.go", caller["file"])
+
+	foreign := got["cause"].(map[string]any)
+	assert.Equal(t, true, foreign["foreign"])
+	assert.Equal(t, "loading user", foreign["message"])
+	assert.Equal(t, true, foreign["own_message"])
+
+	dial := foreign["cause"].(map[string]any)
+	assert.Equal(t, "dial", dial["message"])
+	assert.Equal(t, float64(14), dial["code"])
+	assert.Equal(t, map[string]any{"host": "db.internal"}, dial["fields"])
+	assert.Equal(t, map[string]any{"suggestion": "check the database"}, dial["recovery"])
+
+	leaf := dial["cause"].(map[string]any)
+	assert.Equal(t, "connection refused", leaf["message"])
+	assert.Nil(t, leaf["cause"])
+}
+
+func TestToJSONJoined(t *testing.T) {
+	b, err := terrors.ToJSON(errors.Join(terrors.New("a"), errors.New("b")))
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+
+	assert.Equal(t, "", got["message"])
+
+	causes := got["causes"].([]any)
+	require.Len(t, causes, 2)
+	assert.Equal(t, "a", causes[0].(map[string]any)["message"])
+	assert.Equal(t, "b", causes[1].(map[string]any)["message"])
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	root := errors.New("connection refused")
+	inner := terrors.Wrap(root, "dial").WithCode(14).With("host", "db.internal").WithRecovery("check the database")
+	outer := terrors.Wrap(fmt.Errorf("loading user: %w", inner), "get user").With("user_id", 42)
+
+	b, err := json.Marshal(outer)
+	require.NoError(t, err)
+
+	decoded, err := terrors.FromJSON(b)
+	require.NoError(t, err)
+
+	werr, ok := decoded.(*terrors.WrapError)
+	require.True(t, ok)
+
+	pkg, fn, file, line := werr.Frame().Location()
+	wpkg, wfn, wfile, wline := outer.Frame().Location()
+	assert.Equal(t, []any{wpkg, wfn, wfile, wline}, []any{pkg, fn, file, line})
+	assert.Equal(t, terrors.FormatCallerFromFrame(outer.Frame()), terrors.FormatCallerFromFrame(werr.Frame()))
+
+	chain := terrors.GetChainLinks(decoded)
+	require.Len(t, chain, 4)
+	assert.Equal(t, terrors.LinkForeign, chain[1].Kind)
+	assert.Equal(t, "loading user", chain[1].Message)
+	assert.Equal(t, outer.Unwrap().Error(), chain[1].Err.Error())
+	assert.Equal(t, "connection refused", chain[3].Err.Error())
+
+	dial := terrors.GetDeepestTerror(decoded)
+	require.NotNil(t, dial)
+	assert.Equal(t, terrors.Code(14), dial.Code())
+
+	ok, info := terrors.IsRecoverable(decoded)
+	require.True(t, ok)
+	assert.Equal(t, "check the database", info.Suggestion)
+
+	again, err := json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(b), string(again))
+
+	var target terrors.WrapError
+	require.NoError(t, json.Unmarshal(b, &target))
+	assert.Equal(t, outer.Error(), target.Error())
+}
+
+func TestFromJSONJoined(t *testing.T) {
+	original := errors.Join(terrors.New("a").WithCode(3), errors.New("b"))
+
+	b, err := terrors.ToJSON(original)
+	require.NoError(t, err)
+
+	decoded, err := terrors.FromJSON(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, original.Error(), decoded.Error())
+	require.Len(t, terrors.GetLeaves(decoded), 2)
+	assert.Equal(t, terrors.Code(3), terrors.GetDeepestTerrors(decoded)[0].Code())
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"malformed", `{"message":`},
+		{"wrong type", `{"message":42}`},
+		{"join", `{"message":"x","causes":[{"message":"y"}]}`},
+		{"foreign", `{"message":"x","foreign":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target terrors.WrapError
+			assert.NotPanics(t, func() {
+				assert.Error(t, json.Unmarshal([]byte(tt.data), &target))
+			})
+		})
+	}
+
+	decoded, err := terrors.FromJSON([]byte(`{"message":"x","causes":[{"message":"y"}]}`))
+	require.NoError(t, err)
+	assert.Len(t, terrors.GetLeaves(decoded), 1)
+
+	_, err = terrors.FromJSON([]byte(`{"message":`))
+	assert.Error(t, err)
+}
+
+func TestToJSONForClient(t *testing.T) {
+	inner := terrors.Wrap(errors.New("dial 10.0.0.7:5432"), "query")
+	err := terrors.Wrap(fmt.Errorf("db: %w", inner), "load")
+
+	b, jerr := terrors.ToJSONFor(err, terrors.TargetClient)
+	require.NoError(t, jerr)
+	assert.NotContains(t, string(b), "10.0.0.7")
+	assert.NotContains(t, string(b), "db")
+	assert.NotContains(t, string(b), `"stack"`)
+
+	back, jerr := terrors.FromJSON(b)
+	require.NoError(t, jerr)
+	chain := terrors.GetChainLinks(back)
+	require.Len(t, chain, 2)
+	assert.Equal(t, "load", chain[0].Message)
+	assert.Equal(t, "query", chain[1].Message)
+
+	b, jerr = terrors.ToJSON(err)
+	require.NoError(t, jerr)
+	assert.Contains(t, string(b), "10.0.0.7")
+}
@@ -0,0 +1,107 @@
+package terrors
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// CodePanic marks errors converted from a recovered panic.
+var CodePanic = RegisterCode(-1, CodeInfo{
+	Name:        "PANIC",
+	Description: "a goroutine panicked",
+	HTTPStatus:  500,
+	GRPCCode:    13, // Internal
+})
+
+// Field names set by FromPanic.
+const (
+	PanicValueField = "panic_value"
+	PanicStackField = "panic_stack"
+)
+
+// FromPanic converts a value returned by recover() into a *WrapError whose
+// frame points at the code that panicked. It must be called from the deferred
+// function that recovered. The message is always "panic". A panicked error
+// becomes the cause; the panic value
+// and the goroutine stack are kept as Internal fields, which are never sent to
+// clients, and the error carries CodePanic.
+func FromPanic(v any) *WrapError {
+	frame, ok := panicFrame()
+	if !ok {
+		frame = Caller(1)
+	}
+
+	// The value stays out of the message: it may hold anything and the
+	// message reaches clients.
+	cause, _ := v.(error)
+	we := WrapWithFrame(cause, "panic", frame)
+
+	we.code = CodePanic
+	we.fields = append(we.fields,
+		Field{Key: PanicValueField, Value: v, Sensitivity: Internal},
+		Field{Key: PanicStackField, Value: string(debug.Stack()), Sensitivity: Internal},
+	)
+
+	return we
+}
+
+// Recover converts a panic into an error stored in *errp. It must be deferred
+// directly:
+//
+//	func run() (err error) {
+//		defer terrors.Recover(&err)
+//		...
+//	}
+//
+// If *errp already holds an error, it is joined into the panic's cause.
+func Recover(errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	perr := FromPanic(v)
+	if *errp != nil {
+		perr.err = wrapCause(perr.err, []error{*errp})
+	}
+	*errp = perr
+}
+
+// Go runs fn in a new goroutine and delivers its result, or the recovered
+// panic, on the returned channel, which is closed afterwards.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		defer close(ch)
+
+		var err error
+		func() {
+			defer Recover(&err)
+			err = fn()
+		}()
+		ch <- err
+	}()
+
+	return ch
+}
+
+// panicFrame finds the frame that called panic, skipping the runtime frames
+// between it and runtime.gopanic.
+func panicFrame() (Frame, bool) {
+	frames := runtime.CallersFrames(callers(1))
+
+	inPanic := false
+	for {
+		fr, more := frames.Next()
+		if fr.Function == "runtime.gopanic" {
+			inPanic = true
+		} else if inPanic && !strings.HasPrefix(fr.Function, "runtime.") {
+			return resolvedFrame(fr), true
+		}
+		if !more {
+			return Frame{}, false
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package terrors_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func panicsWith(v any) {
+	panic(v)
+}
+
+func recovered(v any) (err error) {
+	defer terrors.Recover(&err)
+	panicsWith(v)
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	err := recovered("boom")
+	require.Error(t, err)
+
+	werr, ok := terrors.Into[*terrors.WrapError](err)
+	require.True(t, ok)
+
+	_, fn, file, _ := werr.Frame().Location()
+	assert.Equal(t, "panicsWith", fn)
+	assert.Equal(t, "panic_test.go", file)
+
+	assert.Equal(t, terrors.CodePanic, werr.Code())
+	assert.True(t, errors.Is(err, terrors.CodePanic))
+	assert.Equal(t, []any{"panic"}, werr.Info())
+	assert.NotContains(t, err.Error(), "boom")
+	assert.Equal(t, terrors.Fingerprint(err), terrors.Fingerprint(recovered("other value")))
+
+	v, ok := werr.Field(terrors.PanicValueField)
+	require.True(t, ok)
+	assert.Equal(t, "boom", v)
+
+	stack, ok := werr.Field(terrors.PanicStackField)
+	require.True(t, ok)
+	assert.Contains(t, stack, "panicsWith")
+
+	assert.Empty(t, werr.FieldsFor(terrors.TargetClient))
+	assert.Len(t, werr.FieldsFor(terrors.TargetLog), 2)
+	assert.Contains(t, werr.Detail(), "panicsWith")
+}
+
+func TestRecoverErrorCause(t *testing.T) {
+	err := recovered(io.ErrClosedPipe)
+
+	assert.True(t, errors.Is(err, io.ErrClosedPipe))
+	assert.True(t, errors.Is(err, terrors.CodePanic))
+}
+
+func TestRecoverKeepsExistingError(t *testing.T) {
+	run := func() (err error) {
+		defer terrors.Recover(&err)
+		err = io.EOF
+		panicsWith("late")
+		return err
+	}
+
+	err := run()
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.True(t, errors.Is(err, terrors.CodePanic))
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	run := func() (err error) {
+		defer terrors.Recover(&err)
+		return io.EOF
+	}
+
+	assert.Equal(t, io.EOF, run())
+}
+
+func TestGo(t *testing.T) {
+	assert.Equal(t, io.EOF, <-terrors.Go(func() error { return io.EOF }))
+	assert.NoError(t, <-terrors.Go(func() error { return nil }))
+
+	err := <-terrors.Go(func() error {
+		panicsWith("in goroutine")
+		return nil
+	})
+
+	werr, ok := terrors.Into[*terrors.WrapError](err)
+	require.True(t, ok)
+	_, fn, _, _ := werr.Frame().Location()
+	assert.Equal(t, "panicsWith", fn)
+	assert.Equal(t, terrors.CodePanic, werr.Code())
+}
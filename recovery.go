@@ -0,0 +1,170 @@
+package terrors
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// RecoveryOutcome is the result of running a recovery handler.
+type RecoveryOutcome int
+
+const (
+	// OutcomeUnhandled means no handler matched the error.
+	OutcomeUnhandled RecoveryOutcome = iota
+	// OutcomeResolved means the handler fixed the situation.
+	OutcomeResolved
+	// OutcomeFailed means the handler ran and produced a new error.
+	OutcomeFailed
+	// OutcomeEscalated means the handler could not deal with the error and
+	// passed it on to the handlers of outer errors and, eventually, the
+	// caller.
+	OutcomeEscalated
+)
+
+func (o RecoveryOutcome) String() string {
+	switch o {
+	case OutcomeResolved:
+		return "resolved"
+	case OutcomeFailed:
+		return "failed"
+	case OutcomeEscalated:
+		return "escalated"
+	}
+	return "unhandled"
+}
+
+// RecoveryResult is returned by recovery handlers and by
+// (*RecoveryHandlers).Recover.
+type RecoveryResult struct {
+	Outcome RecoveryOutcome
+	// Err is the new error for OutcomeFailed, the escalated error for
+	// OutcomeEscalated and the original error for OutcomeUnhandled.
+	Err error
+	// Info describes the recoverable error the handler ran for.
+	Info *RecoveryInfo
+}
+
+// Resolved reports that a handler fixed the situation.
+func Resolved() RecoveryResult {
+	return RecoveryResult{Outcome: OutcomeResolved}
+}
+
+// Failed reports that a handler ran and failed with err.
+func Failed(err error) RecoveryResult {
+	return RecoveryResult{Outcome: OutcomeFailed, Err: err}
+}
+
+// Escalate reports that a handler cannot deal with the error. err may be nil
+// to escalate the original error.
+func Escalate(err error) RecoveryResult {
+	return RecoveryResult{Outcome: OutcomeEscalated, Err: err}
+}
+
+// RecoveryHandler remediates the recoverable error described by info.
+type RecoveryHandler func(ctx context.Context, info *RecoveryInfo) RecoveryResult
+
+// RecoveryHandlers maps recovery state types and codes to handlers. It is
+// safe for concurrent use.
+type RecoveryHandlers struct {
+	mu      sync.RWMutex
+	byState map[reflect.Type]RecoveryHandler
+	byCode  map[Code]RecoveryHandler
+}
+
+// DefaultRecoveryHandlers is the registry used by programs that need a single
+// one.
+var DefaultRecoveryHandlers = NewRecoveryHandlers()
+
+// NewRecoveryHandlers returns an empty registry.
+func NewRecoveryHandlers() *RecoveryHandlers {
+	return &RecoveryHandlers{
+		byState: map[reflect.Type]RecoveryHandler{},
+		byCode:  map[Code]RecoveryHandler{},
+	}
+}
+
+// HandleCode registers fn for recoverable errors carrying code, replacing
+// any previous handler.
+func (h *RecoveryHandlers) HandleCode(code Code, fn RecoveryHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.byCode[code] = fn
+}
+
+// HandleRecoveryState registers fn for recoverable errors whose recovery
+// state holds a T, as attached by WithRecoveryState. T is matched exactly, so
+// it should be a concrete type. Handlers keyed on the state take precedence
+// over handlers keyed on the code.
+func HandleRecoveryState[T any](h *RecoveryHandlers, fn func(ctx context.Context, state T, info *RecoveryInfo) RecoveryResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.byState[reflect.TypeFor[T]()] = func(ctx context.Context, info *RecoveryInfo) RecoveryResult {
+		for _, s := range info.State {
+			if v, ok := s.(T); ok {
+				return fn(ctx, v, info)
+			}
+		}
+		return RecoveryResult{}
+	}
+}
+
+// Recover runs the handler matching the recoverable errors in err's tree,
+// deepest first, and returns its result. A handler that escalates hands over
+// to the handler of the next, shallower, recoverable error; if none is left
+// the escalation is returned. Without any matching handler the result is
+// OutcomeUnhandled with err.
+func (h *RecoveryHandlers) Recover(ctx context.Context, err error) RecoveryResult {
+	infos := AllRecoverable(err)
+	slices.SortStableFunc(infos, func(a, b *RecoveryInfo) int {
+		return cmp.Compare(b.Depth, a.Depth)
+	})
+
+	result := RecoveryResult{Outcome: OutcomeUnhandled, Err: err}
+
+	for _, info := range infos {
+		fn := h.handlerFor(info)
+		if fn == nil {
+			continue
+		}
+
+		res := fn(ctx, info)
+		if res.Outcome == OutcomeUnhandled {
+			continue
+		}
+		res.Info = info
+
+		if res.Outcome != OutcomeEscalated {
+			return res
+		}
+		if res.Err == nil {
+			res.Err = err
+		}
+		result = res
+	}
+
+	return result
+}
+
+func (h *RecoveryHandlers) handlerFor(info *RecoveryInfo) RecoveryHandler {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, s := range info.State {
+		if fn, ok := h.byState[reflect.TypeOf(s)]; ok {
+			return fn
+		}
+	}
+
+	if code := CodeOf(info.Err); code != 0 {
+		if fn, ok := h.byCode[code]; ok {
+			return fn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package terrors_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+type reloginState struct {
+	Account string
+}
+
+type backoffState struct {
+	Seconds int
+}
+
+func TestRecoveryState(t *testing.T) {
+	inner := terrors.WithRecoveryState(terrors.Wrap(io.EOF, "token expired"), "log in again", reloginState{Account: "alice"})
+	outer := terrors.WithRecoveryState(terrors.Wrap(inner, "sync failed"), "wait and retry", backoffState{Seconds: 5})
+
+	relogin, ok := terrors.RecoveryState[reloginState](outer)
+	require.True(t, ok)
+	assert.Equal(t, "alice", relogin.Account)
+
+	backoff, ok := terrors.RecoveryState[backoffState](outer)
+	require.True(t, ok)
+	assert.Equal(t, 5, backoff.Seconds)
+
+	_, ok = terrors.RecoveryState[string](outer)
+	assert.False(t, ok)
+
+	_, ok = terrors.RecoveryState[reloginState](io.EOF)
+	assert.False(t, ok)
+}
+
+func TestRecoveryInfo(t *testing.T) {
+	inner := terrors.New("quota exceeded").WithRecovery("upgrade plan", "pro")
+	outer := terrors.Wrap(inner, "upload").WithRecovery("try a smaller file")
+
+	ok, info := terrors.IsRecoverable(outer)
+	require.True(t, ok)
+	assert.Equal(t, "upgrade plan", info.Suggestion)
+	assert.Equal(t, []any{"pro"}, info.State)
+	assert.Same(t, inner, info.Err)
+	assert.Equal(t, inner.Recovery().Frame, info.Frame)
+	assert.Equal(t, 1, info.Depth)
+
+	_, fn, file, _ := info.Frame.Location()
+	assert.Equal(t, "TestRecoveryInfo", fn)
+	assert.Equal(t, "recovery_test.go", file)
+}
+
+func createQuotaError() *terrors.WrapError {
+	return terrors.New("quota exceeded")
+}
+
+func attachQuotaRecovery(err *terrors.WrapError) *terrors.WrapError {
+	return err.WithRecoveryf("upgrade to %s", "pro")
+}
+
+func attachQuotaState(err *terrors.WrapError) *terrors.WrapError {
+	return terrors.WithRecoveryState(err, "upgrade", backoffState{Seconds: 1})
+}
+
+func TestRecoveryInfoFrame(t *testing.T) {
+	err := createQuotaError()
+
+	for want, attach := range map[string]func(*terrors.WrapError) *terrors.WrapError{
+		"attachQuotaRecovery": attachQuotaRecovery,
+		"attachQuotaState":    attachQuotaState,
+	} {
+		ok, info := terrors.IsRecoverable(attach(err))
+		require.True(t, ok)
+
+		_, fn, file, _ := info.Frame.Location()
+		assert.Equal(t, want, fn)
+		assert.Equal(t, "recovery_test.go", file)
+
+		_, created, _, _ := info.Err.Frame().Location()
+		assert.Equal(t, "createQuotaError", created)
+	}
+}
+
+func TestAllRecoverable(t *testing.T) {
+	a := terrors.New("a").WithRecovery("fix a")
+	b := terrors.New("b").WithRecovery("fix b")
+	top := terrors.Wrap(errors.Join(a, terrors.New("plain"), b), "top").WithRecovery("fix top")
+
+	var got []string
+	for _, info := range terrors.AllRecoverable(top) {
+		got = append(got, info.Suggestion)
+	}
+	assert.Equal(t, []string{"fix top", "fix a", "fix b"}, got)
+
+	assert.Empty(t, terrors.AllRecoverable(io.EOF))
+}
+
+var codeQuota = terrors.RegisterCode(9004, terrors.CodeInfo{Name: "STORAGE_QUOTA"})
+
+func TestRecoveryHandlers(t *testing.T) {
+	handlers := terrors.NewRecoveryHandlers()
+
+	var relogged string
+	terrors.HandleRecoveryState(handlers, func(_ context.Context, s reloginState, _ *terrors.RecoveryInfo) terrors.RecoveryResult {
+		relogged = s.Account
+		return terrors.Resolved()
+	})
+
+	errUpgrade := errors.New("upgrade required")
+	handlers.HandleCode(codeQuota, func(context.Context, *terrors.RecoveryInfo) terrors.RecoveryResult {
+		return terrors.Failed(errUpgrade)
+	})
+
+	err := terrors.Wrap(terrors.WithRecoveryState(terrors.New("token expired"), "log in again", reloginState{Account: "alice"}), "sync")
+	res := handlers.Recover(context.Background(), err)
+	assert.Equal(t, terrors.OutcomeResolved, res.Outcome)
+	assert.Equal(t, "alice", relogged)
+	require.NotNil(t, res.Info)
+	assert.Equal(t, "log in again", res.Info.Suggestion)
+
+	res = handlers.Recover(context.Background(), terrors.New("quota").WithCode(codeQuota).WithRecovery("upgrade"))
+	assert.Equal(t, terrors.OutcomeFailed, res.Outcome)
+	assert.Equal(t, errUpgrade, res.Err)
+
+	plain := terrors.New("nothing to do").WithRecovery("ignore")
+	res = handlers.Recover(context.Background(), plain)
+	assert.Equal(t, terrors.OutcomeUnhandled, res.Outcome)
+	assert.Equal(t, plain, res.Err)
+
+	res = handlers.Recover(context.Background(), io.EOF)
+	assert.Equal(t, terrors.OutcomeUnhandled, res.Outcome)
+}
+
+func TestRecoveryHandlersEscalation(t *testing.T) {
+	handlers := terrors.NewRecoveryHandlers()
+
+	var order []string
+	terrors.HandleRecoveryState(handlers, func(_ context.Context, _ backoffState, _ *terrors.RecoveryInfo) terrors.RecoveryResult {
+		order = append(order, "backoff")
+		return terrors.Escalate(nil)
+	})
+	terrors.HandleRecoveryState(handlers, func(_ context.Context, _ reloginState, _ *terrors.RecoveryInfo) terrors.RecoveryResult {
+		order = append(order, "relogin")
+		return terrors.Resolved()
+	})
+
+	inner := terrors.WithRecoveryState(terrors.New("throttled"), "wait", backoffState{Seconds: 1})
+	outer := terrors.WithRecoveryState(terrors.Wrap(inner, "session"), "log in", reloginState{})
+
+	res := handlers.Recover(context.Background(), outer)
+	assert.Equal(t, terrors.OutcomeResolved, res.Outcome)
+	assert.Equal(t, []string{"backoff", "relogin"}, order)
+
+	res = handlers.Recover(context.Background(), inner)
+	assert.Equal(t, terrors.OutcomeEscalated, res.Outcome)
+	assert.Equal(t, inner, res.Err)
+}
@@ -0,0 +1,226 @@
+package terrors
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Sensitivity classifies a field for redaction.
+type Sensitivity int
+
+const (
+	// Public fields are rendered as is.
+	Public Sensitivity = iota
+	// Internal fields are kept in the process' own output but never sent to
+	// clients, e.g. stack dumps.
+	Internal
+	// PII fields hold personal data.
+	PII
+	// Secret fields hold credentials, tokens and the like.
+	Secret
+)
+
+// Target is an output an error's fields are rendered to.
+type Target int
+
+const (
+	// TargetConsole is human facing output: Detail and FullChainFormatter.
+	TargetConsole Target = iota
+	// TargetLog is machine output kept internally: zerolog, slog, JSON and
+	// telemetry.
+	TargetLog
+	// TargetClient is output sent to callers outside the process, such as
+	// HTTP problem details and gRPC status details.
+	TargetClient
+)
+
+// RedactAction is what happens to a field for a target.
+type RedactAction int
+
+const (
+	// Keep renders the value as is.
+	Keep RedactAction = iota
+	// Mask replaces the value with MaskedValue.
+	Mask
+	// Hash replaces the value with a keyed HMAC-SHA256 digest so equal values
+	// can still be correlated without being guessable. See SetHashKey.
+	Hash
+	// Drop removes the field.
+	Drop
+)
+
+// MaskedValue replaces masked field values.
+const MaskedValue = "[REDACTED]"
+
+// Redactor decides how a field is rendered for a target. Returning false
+// drops the field.
+type Redactor func(target Target, f Field) (Field, bool)
+
+type keyRule struct {
+	pattern     string
+	sensitivity Sensitivity
+}
+
+var (
+	redactMu sync.RWMutex
+	redactor Redactor
+	keyRules []keyRule
+	typeRule = map[reflect.Type]Sensitivity{}
+	hashKey  = randomHashKey()
+	actions  = map[Sensitivity]map[Target]RedactAction{
+		Internal: {TargetConsole: Keep, TargetLog: Keep, TargetClient: Drop},
+		PII:      {TargetConsole: Keep, TargetLog: Hash, TargetClient: Drop},
+		Secret:   {TargetConsole: Mask, TargetLog: Mask, TargetClient: Drop},
+	}
+)
+
+// WithSecret attaches a field that is masked or dropped in every output.
+func (e *WrapError) WithSecret(name string, value any) *WrapError {
+	c := e.clone()
+	c.fields = append(c.fields, Field{Key: name, Value: value, Sensitivity: Secret})
+	return c
+}
+
+// WithPII attaches a field holding personal data.
+func (e *WrapError) WithPII(name string, value any) *WrapError {
+	c := e.clone()
+	c.fields = append(c.fields, Field{Key: name, Value: value, Sensitivity: PII})
+	return c
+}
+
+// MarkKeys classifies every field whose key matches the path.Match pattern,
+// case-insensitively, e.g. MarkKeys("*token*", Secret).
+func MarkKeys(pattern string, s Sensitivity) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	keyRules = append(keyRules, keyRule{pattern: strings.ToLower(pattern), sensitivity: s})
+}
+
+// MarkType classifies every field whose value has type T.
+func MarkType[T any](s Sensitivity) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	typeRule[reflect.TypeFor[T]()] = s
+}
+
+// SetRedactAction changes what happens to fields of sensitivity s for target.
+func SetRedactAction(s Sensitivity, target Target, action RedactAction) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	if actions[s] == nil {
+		actions[s] = map[Target]RedactAction{}
+	}
+	actions[s][target] = action
+}
+
+// SetHashKey sets the key Hash digests are computed with. Processes that
+// share a key produce the same digest for the same value; without one, a
+// random key is used and digests only match within the process.
+func SetHashKey(key []byte) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	hashKey = slices.Clone(key)
+}
+
+func randomHashKey() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// SetRedactor replaces the redaction policy of the whole program. A nil
+// redactor restores DefaultRedactor.
+func SetRedactor(r Redactor) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+
+	redactor = r
+}
+
+// Classify returns the sensitivity of f: the highest of its own, the key
+// patterns it matches and the type of its value.
+func Classify(f Field) Sensitivity {
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+
+	s := f.Sensitivity
+
+	key := strings.ToLower(f.Key)
+	for _, rule := range keyRules {
+		if ok, _ := path.Match(rule.pattern, key); ok && rule.sensitivity > s {
+			s = rule.sensitivity
+		}
+	}
+
+	if f.Value != nil {
+		if ts, ok := typeRule[reflect.TypeOf(f.Value)]; ok && ts > s {
+			s = ts
+		}
+	}
+
+	return s
+}
+
+// DefaultRedactor applies the action configured for the field's
+// classification and target.
+func DefaultRedactor(target Target, f Field) (Field, bool) {
+	s := Classify(f)
+	if s == Public {
+		return f, true
+	}
+
+	redactMu.RLock()
+	action := actions[s][target]
+	key := hashKey
+	redactMu.RUnlock()
+
+	switch action {
+	case Mask:
+		f.Value = MaskedValue
+	case Hash:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(fmt.Sprint(f.Value)))
+		f.Value = "hmac:" + hex.EncodeToString(mac.Sum(nil)[:16])
+	case Drop:
+		return f, false
+	}
+
+	return f, true
+}
+
+// Redact applies the program's redaction policy to fields for target.
+func Redact(target Target, fields []Field) []Field {
+	redactMu.RLock()
+	r := redactor
+	redactMu.RUnlock()
+
+	if r == nil {
+		r = DefaultRedactor
+	}
+
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if rf, ok := r(target, f); ok {
+			out = append(out, rf)
+		}
+	}
+
+	return out
+}
+
+// FieldsFor returns the fields of e as they should be rendered for target.
+func (e *WrapError) FieldsFor(target Target) []Field {
+	return Redact(target, e.Fields())
+}
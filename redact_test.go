@@ -0,0 +1,88 @@
+package terrors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+type creditCard string
+
+func init() {
+	terrors.MarkKeys("*_token", terrors.Secret)
+	terrors.MarkType[creditCard](terrors.PII)
+}
+
+func TestRedactionTargets(t *testing.T) {
+	err := terrors.New("login failed").
+		With("user", "bob").
+		With("session_token", "tok-123").
+		WithSecret("password", "hunter2").
+		WithPII("email", "bob@example.com").
+		With("card", creditCard("4111"))
+
+	console := err.FieldsFor(terrors.TargetConsole)
+	assert.Equal(t, []terrors.Field{
+		{Key: "user", Value: "bob"},
+		{Key: "session_token", Value: terrors.MaskedValue},
+		{Key: "password", Value: terrors.MaskedValue, Sensitivity: terrors.Secret},
+		{Key: "email", Value: "bob@example.com", Sensitivity: terrors.PII},
+		{Key: "card", Value: creditCard("4111")},
+	}, console)
+
+	logged := err.FieldsFor(terrors.TargetLog)
+	require.Len(t, logged, 5)
+	assert.Equal(t, terrors.MaskedValue, logged[2].Value)
+	assert.True(t, strings.HasPrefix(logged[3].Value.(string), "hmac:"))
+	assert.Len(t, logged[3].Value, len("hmac:")+32)
+	assert.Equal(t, logged[3].Value, terrors.Redact(terrors.TargetLog, []terrors.Field{{Key: "x", Value: "bob@example.com", Sensitivity: terrors.PII}})[0].Value)
+
+	client := err.FieldsFor(terrors.TargetClient)
+	assert.Equal(t, []terrors.Field{{Key: "user", Value: "bob"}}, client)
+
+	assert.NotContains(t, err.Detail(), "hunter2")
+	assert.NotContains(t, err.Detail(), "tok-123")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+	assert.NotContains(t, string(b), "hunter2")
+	assert.NotContains(t, string(b), "bob@example.com")
+
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+	logger.Error().Func(func(e *zerolog.Event) { _ = err.MarshalZerologObject(e) }).Send()
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), `"user":"bob"`)
+
+	b, jerr = terrors.ToJSONFor(err, terrors.TargetClient)
+	require.NoError(t, jerr)
+	assert.NotContains(t, string(b), "password")
+}
+
+func TestCustomRedactor(t *testing.T) {
+	terrors.SetRedactor(func(target terrors.Target, f terrors.Field) (terrors.Field, bool) {
+		return f, f.Key != "user"
+	})
+	defer terrors.SetRedactor(nil)
+
+	err := terrors.New("x").With("user", "bob").WithSecret("password", "hunter2")
+	assert.Equal(t, []terrors.Field{{Key: "password", Value: "hunter2", Sensitivity: terrors.Secret}}, err.FieldsFor(terrors.TargetClient))
+}
+
+func TestHashKey(t *testing.T) {
+	field := []terrors.Field{{Key: "email", Value: "bob@example.com", Sensitivity: terrors.PII}}
+
+	terrors.SetHashKey([]byte("key-a"))
+	a := terrors.Redact(terrors.TargetLog, field)[0].Value
+	assert.Equal(t, a, terrors.Redact(terrors.TargetLog, field)[0].Value)
+
+	terrors.SetHashKey([]byte("key-b"))
+	b := terrors.Redact(terrors.TargetLog, field)[0].Value
+	assert.NotEqual(t, a, b)
+}
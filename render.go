@@ -0,0 +1,318 @@
+package terrors
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// Renderer controls how errors are turned into text. Error, Detail and the
+// chain formatters only assemble the pieces; every decoration comes from the
+// Renderer in use.
+type Renderer interface {
+	// Label renders a labelled value, e.g. the message of an error.
+	Label(label, value string) string
+	// Code renders an error code.
+	Code(code Code) string
+	// Caller renders a source location.
+	Caller(pkg, file string, line int) string
+	// Link joins an error with the rendered error it wraps.
+	Link(self, kid string) string
+	// Branches renders the branches of a joined error.
+	Branches(branches []string) string
+	// Root marks the deepest error of a chain.
+	Root(text string) string
+	// Entry renders one error of the multi-line chain output. last is set
+	// for the deepest error.
+	Entry(text string, last bool) string
+	// JoinHeader introduces the branches of a joined error in the
+	// multi-line chain output.
+	JoinHeader(count int) string
+	// Emphasis highlights an error in FormatErrorCaller.
+	Emphasis(text string) string
+}
+
+var defaultRenderer atomic.Value
+
+func init() {
+	defaultRenderer.Store(rendererHolder{PlainRenderer{}})
+}
+
+// rendererHolder gives atomic.Value a single concrete type to store.
+type rendererHolder struct {
+	Renderer
+}
+
+// SetDefaultRenderer sets the Renderer used by Error and the formatters of
+// this package. A nil renderer restores PlainRenderer.
+func SetDefaultRenderer(r Renderer) {
+	if r == nil {
+		r = PlainRenderer{}
+	}
+	defaultRenderer.Store(rendererHolder{r})
+}
+
+// DefaultRenderer returns the Renderer set with SetDefaultRenderer.
+func DefaultRenderer() Renderer {
+	return defaultRenderer.Load().(rendererHolder).Renderer
+}
+
+// PlainRenderer renders stable, undecorated text. It is the default, so
+// Error returns strings safe for log parsers and test assertions.
+type PlainRenderer struct{}
+
+func (PlainRenderer) Label(label, value string) string {
+	return fmt.Sprintf("[%s=%s]", label, value)
+}
+
+func (PlainRenderer) Code(code Code) string {
+	return fmt.Sprintf("{code=%s}", code)
+}
+
+func (PlainRenderer) Caller(pkg, file string, line int) string {
+	return fmt.Sprintf("[pkg=%s][file=%s:%d]", pkg, file, line)
+}
+
+func (PlainRenderer) Link(self, kid string) string {
+	return self + " -> " + kid
+}
+
+func (PlainRenderer) Branches(branches []string) string {
+	return "[ " + strings.Join(branches, " | ") + " ]"
+}
+
+func (PlainRenderer) Root(text string) string {
+	return text
+}
+
+func (PlainRenderer) Entry(text string, last bool) string {
+	if last {
+		return "x " + text
+	}
+	return "- " + text
+}
+
+func (PlainRenderer) JoinHeader(count int) string {
+	return fmt.Sprintf("+ joined %d errors", count)
+}
+
+func (PlainRenderer) Emphasis(text string) string {
+	return text
+}
+
+// ANSIRenderer renders colored terminal output with emoji markers. Colors
+// are disabled automatically when output is not a terminal.
+type ANSIRenderer struct{}
+
+func (ANSIRenderer) Label(label, value string) string {
+	return ColorBrackets(label, value)
+}
+
+func (ANSIRenderer) Code(code Code) string {
+	return ColorCode(code)
+}
+
+func (ANSIRenderer) Caller(pkg, file string, line int) string {
+	pkgd := ColorBrackets("pkg", color.New(color.FgHiGreen).Sprint(pkg))
+	pathd := ColorBrackets("file", fmt.Sprintf("%s:%s", color.New(color.Bold).Sprint(file), color.New(color.FgHiRed, color.Bold).Sprintf("%d", line)))
+	return fmt.Sprintf("%s%s", pkgd, pathd)
+}
+
+func (ANSIRenderer) Link(self, kid string) string {
+	return self + " 👉 " + kid
+}
+
+func (ANSIRenderer) Branches(branches []string) string {
+	return "[ " + strings.Join(branches, " | ") + " ]"
+}
+
+func (ANSIRenderer) Root(text string) string {
+	return "❌ " + text
+}
+
+func (ANSIRenderer) Entry(text string, last bool) string {
+	if last {
+		return "❌ " + text
+	}
+	return "👇 " + text
+}
+
+func (ANSIRenderer) JoinHeader(count int) string {
+	return fmt.Sprintf("🔀 joined %d errors", count)
+}
+
+func (ANSIRenderer) Emphasis(text string) string {
+	return color.New(color.FgRed).Sprint(text)
+}
+
+// MarkdownRenderer renders inline code spans and lists, for issue trackers
+// and chat messages.
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Label(label, value string) string {
+	return fmt.Sprintf(" `%s=%s`", label, value)
+}
+
+func (MarkdownRenderer) Code(code Code) string {
+	return fmt.Sprintf(" `code=%s`", code)
+}
+
+func (MarkdownRenderer) Caller(pkg, file string, line int) string {
+	return fmt.Sprintf(" `pkg=%s` `file=%s:%d`", pkg, file, line)
+}
+
+func (MarkdownRenderer) Link(self, kid string) string {
+	return self + " → " + kid
+}
+
+func (MarkdownRenderer) Branches(branches []string) string {
+	return "( " + strings.Join(branches, " | ") + " )"
+}
+
+func (MarkdownRenderer) Root(text string) string {
+	return "**" + text + "**"
+}
+
+func (MarkdownRenderer) Entry(text string, last bool) string {
+	if last {
+		return "- **root cause:** " + text
+	}
+	return "- " + text
+}
+
+func (MarkdownRenderer) JoinHeader(count int) string {
+	return fmt.Sprintf("- joined %d errors:", count)
+}
+
+func (MarkdownRenderer) Emphasis(text string) string {
+	return "**" + text + "**"
+}
+
+// ErrorWith renders the inline chain of e, like Error, using r.
+func (e *WrapError) ErrorWith(r Renderer) string {
+	return renderInline(r, e.selfWith(r), e.err)
+}
+
+func (e *WrapError) messageWith(r Renderer) string {
+	if e.code != 0 {
+		return fmt.Sprintf("ERROR%s%s", r.Code(e.code), r.Label("msg", e.msg))
+	}
+	return fmt.Sprintf("ERROR%s", r.Label("msg", e.msg))
+}
+
+func (e *WrapError) selfWith(r Renderer) string {
+	pkg, _, file, line := e.frame.Location()
+	return e.messageWith(r) + r.Caller(pkg, file, line)
+}
+
+// renderInline renders self followed by the chain below it on one line.
+func renderInline(r Renderer, self string, kid error) string {
+	if kid == nil {
+		return r.Root(self)
+	}
+
+	return r.Link(self, renderError(r, kid))
+}
+
+// renderError renders err and the chain below it on one line.
+func renderError(r Renderer, err error) string {
+	if werr, ok := err.(*WrapError); ok {
+		return werr.ErrorWith(r)
+	}
+
+	kids := unwrapChildren(err)
+
+	if _, ok := err.(multiUnwrapper); ok && len(kids) > 0 {
+		branches := make([]string, 0, len(kids))
+		for _, kid := range kids {
+			branches = append(branches, renderError(r, kid))
+		}
+		return r.Branches(branches)
+	}
+
+	if own, ok := ownMessage(err); ok {
+		// render foreign wrappers link by link so the terrors they wrap
+		// keep their own formatting
+		if own == "" {
+			return renderError(r, kids[0])
+		}
+		return renderInline(r, own, kids[0])
+	}
+
+	return r.Root(err.Error())
+}
+
+// RenderChain renders the multi-line view of err, like FullChainFormatter,
+// using r.
+func RenderChain(r Renderer, err error) string {
+	wrk := &strings.Builder{}
+
+	wrk.WriteString("\n\n")
+
+	writeFullChain(wrk, r, err, "")
+
+	wrk.WriteString("\n\n")
+
+	return wrk.String()
+}
+
+func writeFullChain(wrk *strings.Builder, r Renderer, err error, indent string) {
+	for err != nil {
+		kids := unwrapChildren(err)
+
+		if _, ok := err.(multiUnwrapper); ok {
+			wrk.WriteString(indent + r.JoinHeader(len(kids)) + "\n\n")
+			for _, kid := range kids {
+				writeFullChain(wrk, r, kid, indent+"    ")
+			}
+			return
+		}
+
+		last := len(kids) == 0
+
+		switch v := err.(type) {
+		case *WrapError:
+			wrk.WriteString(indentLines(r.Entry(v.detailedSelfWith(r), last), indent))
+		default:
+			own, ok := ownMessage(v)
+			// a message that does not repeat its cause's already covers it
+			last = last || !ok
+			if own != "" {
+				wrk.WriteString(indentLines(r.Entry(own+"\n\n", last), indent))
+			}
+		}
+
+		if last {
+			return
+		}
+
+		err = kids[0]
+	}
+}
+
+func (e *WrapError) detailedSelfWith(r Renderer) string {
+	self := e.selfWith(r)
+
+	if dets := e.Detail(); dets != "" {
+		self += fmt.Sprintf("\n\n%s\n\n", dets)
+	}
+
+	return self
+}
+
+func indentLines(s string, indent string) string {
+	if indent == "" {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package terrors_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/walteh/terrors"
+)
+
+func TestRenderers(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	err := terrors.Wrap(errors.Join(terrors.New("first").WithCode(7), errors.New("second")), "top")
+	_, _, _, line := err.Frame().Location()
+
+	self := func(msg string, r terrors.Renderer) string {
+		return "ERROR" + r.Label("msg", msg) + r.Caller("walteh/terrors_test", "render_test.go", line)
+	}
+
+	plain := terrors.PlainRenderer{}
+	assert.Equal(t,
+		self("top", plain)+" -> [ ERROR{code=7}"+plain.Label("msg", "first")+plain.Caller("walteh/terrors_test", "render_test.go", line)+" | second ]",
+		err.Error())
+	assert.Equal(t, err.Error(), err.ErrorWith(plain))
+
+	ansi := terrors.ANSIRenderer{}
+	assert.Equal(t,
+		"ERROR[msg=top][pkg=walteh/terrors_test][file=render_test.go:"+strconv.Itoa(line)+"] 👉 [ ❌ ERROR{code=7}[msg=first][pkg=walteh/terrors_test][file=render_test.go:"+strconv.Itoa(line)+"] | ❌ second ]",
+		err.ErrorWith(ansi))
+
+	md := terrors.MarkdownRenderer{}
+	assert.Equal(t,
+		"ERROR `msg=top` `pkg=walteh/terrors_test` `file=render_test.go:"+strconv.Itoa(line)+"` → ( **ERROR `code=7` `msg=first` `pkg=walteh/terrors_test` `file=render_test.go:"+strconv.Itoa(line)+"`** | **second** )",
+		err.ErrorWith(md))
+
+	assert.Contains(t, terrors.RenderChain(ansi, err), "🔀 joined 2 errors")
+	assert.Contains(t, terrors.RenderChain(md, err), "- **root cause:** second")
+}
+
+func TestDefaultRenderer(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	terrors.SetDefaultRenderer(terrors.ANSIRenderer{})
+	defer terrors.SetDefaultRenderer(nil)
+
+	err := terrors.New("boom")
+	assert.Contains(t, err.Error(), "❌ ERROR[msg=boom]")
+	assert.Contains(t, terrors.FormatErrorCallerWith(terrors.PlainRenderer{}, err, "x", false), "[x] - ERROR[msg=boom]")
+
+	terrors.SetDefaultRenderer(nil)
+	assert.IsType(t, terrors.PlainRenderer{}, terrors.DefaultRenderer())
+	assert.NotContains(t, err.Error(), "❌")
+}
@@ -0,0 +1,236 @@
+package terrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Retryability classifies whether the operation that produced an error may
+// be retried.
+type Retryability int
+
+const (
+	// RetryUnspecified means the error carries no retry semantics.
+	RetryUnspecified Retryability = iota
+	// RetryAllowed means the operation may be retried.
+	RetryAllowed
+	// RetryPermanent means retrying cannot succeed.
+	RetryPermanent
+)
+
+func (r Retryability) String() string {
+	switch r {
+	case RetryAllowed:
+		return "retryable"
+	case RetryPermanent:
+		return "permanent"
+	}
+	return "unspecified"
+}
+
+// RetryHint holds the retry semantics attached to an error.
+type RetryHint struct {
+	Retryability Retryability
+	// After is the minimum delay before the next attempt, or 0 for none.
+	After time.Duration
+	// MaxAttempts caps the number of attempts, or 0 for no cap.
+	MaxAttempts int
+}
+
+// RetryHint returns the retry semantics attached to e itself.
+func (e *WrapError) RetryHint() RetryHint {
+	return e.retry
+}
+
+// WithRetryable marks e as retryable.
+func (e *WrapError) WithRetryable() *WrapError {
+	c := e.clone()
+	c.retry.Retryability = RetryAllowed
+	return c
+}
+
+// WithPermanent marks e as not retryable.
+func (e *WrapError) WithPermanent() *WrapError {
+	c := e.clone()
+	c.retry.Retryability = RetryPermanent
+	return c
+}
+
+// WithRetryAfter marks e as retryable no sooner than d.
+func (e *WrapError) WithRetryAfter(d time.Duration) *WrapError {
+	c := e.clone()
+	c.retry.Retryability = RetryAllowed
+	c.retry.After = d
+	return c
+}
+
+// WithMaxAttempts caps how many times the operation that produced e may be
+// attempted.
+func (e *WrapError) WithMaxAttempts(n int) *WrapError {
+	c := e.clone()
+	c.retry.MaxAttempts = n
+	return c
+}
+
+// RetryHintOf merges the retry semantics of every terror in err's tree,
+// outermost first: the outermost terror that sets a value wins. An error
+// without an explicit retryability is retryable if any of its codes is
+// registered as Retryable.
+func RetryHintOf(err error) RetryHint {
+	var hint RetryHint
+	codeRetryable := false
+
+	Walk(err, func(e error, _ int) bool {
+		werr, ok := e.(*WrapError)
+		if !ok {
+			return true
+		}
+		if hint.Retryability == RetryUnspecified {
+			hint.Retryability = werr.retry.Retryability
+		}
+		if hint.After == 0 {
+			hint.After = werr.retry.After
+		}
+		if hint.MaxAttempts == 0 {
+			hint.MaxAttempts = werr.retry.MaxAttempts
+		}
+		if info, ok := werr.code.Info(); ok && info.Retryable {
+			codeRetryable = true
+		}
+		return true
+	})
+
+	if hint.Retryability == RetryUnspecified && codeRetryable {
+		hint.Retryability = RetryAllowed
+	}
+
+	return hint
+}
+
+// IsRetryable reports whether err is explicitly retryable or carries a code
+// registered as Retryable, and is not marked permanent.
+func IsRetryable(err error) bool {
+	return err != nil && RetryHintOf(err).Retryability == RetryAllowed
+}
+
+// IsPermanent reports whether err is marked as not retryable.
+func IsPermanent(err error) bool {
+	return err != nil && RetryHintOf(err).Retryability == RetryPermanent
+}
+
+// RetryAfter returns the delay requested by the outermost terror that sets
+// one.
+func RetryAfter(err error) (time.Duration, bool) {
+	d := RetryHintOf(err).After
+	return d, d > 0
+}
+
+// RetryPolicy configures Retry. The zero value makes up to 3 attempts with
+// an exponential backoff starting at 100ms.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	MaxAttempts int
+	// InitialDelay is the delay after the first failed attempt.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts, or 0 for no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after every attempt. Values below 1 mean 2.
+	Multiplier float64
+	// RetryUnspecified also retries errors that carry no retry semantics.
+	RetryUnspecified bool
+}
+
+// Retry calls fn until it succeeds, returns an error that may not be
+// retried, the attempts allowed by policy and the errors run out, or ctx is
+// done. A retry-after on the error replaces the backoff delay for that
+// attempt.
+//
+// Each failed attempt is wrapped with its attempt number and duration, and
+// the returned terror joins all of them, so errors.Is and CodeOf still see
+// every attempt's error.
+func Retry(ctx context.Context, fn func(ctx context.Context) error, policy RetryPolicy) error {
+	frame := Caller(1)
+	policy = policy.withDefaults()
+
+	var attempts []error
+	start := time.Now()
+	delay := policy.InitialDelay
+	maxAttempts := policy.MaxAttempts
+	tried := 0
+
+	for n := 1; ; n++ {
+		tried = n
+		began := time.Now()
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		attempt := WrapWithFrame(err, fmt.Sprintf("attempt %d", n), frame).withTemplate("attempt %d", []any{n})
+		attempt.fields = append(attempt.fields,
+			Field{Key: "attempt", Value: n},
+			Field{Key: "duration", Value: time.Since(began)},
+		)
+		attempts = append(attempts, attempt)
+
+		hint := RetryHintOf(err)
+		if hint.MaxAttempts > 0 && hint.MaxAttempts < maxAttempts {
+			maxAttempts = hint.MaxAttempts
+		}
+
+		retry := hint.Retryability == RetryAllowed ||
+			(hint.Retryability == RetryUnspecified && policy.RetryUnspecified)
+		if !retry || n >= maxAttempts {
+			break
+		}
+
+		wait := delay
+		if hint.After > 0 {
+			wait = hint.After
+		}
+
+		if werr := sleep(ctx, wait); werr != nil {
+			attempts = append(attempts, werr)
+			break
+		}
+
+		delay = time.Duration(float64(delay) * policy.Multiplier)
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+
+	const format = "failed after %d attempts"
+	summary := WrapWithFrame(errors.Join(attempts...), fmt.Sprintf(format, tried), frame).withTemplate(format, []any{tried})
+	summary.fields = append(summary.fields, Field{Key: "elapsed", Value: time.Since(start)})
+
+	return summary
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = 100 * time.Millisecond
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	return p
+}
+
+// sleep waits for d or until ctx is done, returning ctx.Err() in that case.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,196 @@
+package terrors_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+var codeUnavailable = terrors.RegisterCode(9003, terrors.CodeInfo{Name: "UNAVAILABLE", Retryable: true})
+
+func TestRetryClassification(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		permanent bool
+	}{
+		{"plain", io.EOF, false, false},
+		{"unspecified terror", terrors.New("x"), false, false},
+		{"retryable", terrors.New("x").WithRetryable(), true, false},
+		{"permanent", terrors.New("x").WithPermanent(), false, true},
+		{"retry after", terrors.New("x").WithRetryAfter(time.Second), true, false},
+		{"retryable code", terrors.New("x").WithCode(codeUnavailable), true, false},
+		{"outer permanent wins over code", terrors.Wrap(terrors.New("x").WithCode(codeUnavailable), "y").WithPermanent(), false, true},
+		{"outer wins", terrors.Wrap(terrors.New("x").WithPermanent(), "y").WithRetryable(), true, false},
+		{"inner through foreign", terrors.Wrap(wrapForeign(terrors.New("x").WithRetryable()), "y"), true, false},
+		{"nil", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, terrors.IsRetryable(tt.err))
+			assert.Equal(t, tt.permanent, terrors.IsPermanent(tt.err))
+		})
+	}
+}
+
+type foreignWrapper struct{ err error }
+
+func (f foreignWrapper) Error() string { return "foreign: " + f.err.Error() }
+func (f foreignWrapper) Unwrap() error { return f.err }
+
+func wrapForeign(err error) error { return foreignWrapper{err} }
+
+func TestRetryHintOf(t *testing.T) {
+	err := terrors.Wrap(terrors.New("x").WithRetryAfter(time.Second).WithMaxAttempts(5), "y").WithMaxAttempts(2)
+
+	hint := terrors.RetryHintOf(err)
+	assert.Equal(t, terrors.RetryAllowed, hint.Retryability)
+	assert.Equal(t, time.Second, hint.After)
+	assert.Equal(t, 2, hint.MaxAttempts)
+
+	d, ok := terrors.RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+}
+
+func TestRetry(t *testing.T) {
+	policy := terrors.RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond}
+
+	calls := 0
+	err := terrors.Retry(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return terrors.New("flaky").WithRetryable()
+		}
+		return nil
+	}, policy)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryExhausted(t *testing.T) {
+	calls := 0
+	err := terrors.Retry(context.Background(), func(context.Context) error {
+		calls++
+		return terrors.New("down").WithCode(codeUnavailable)
+	}, terrors.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+	assert.True(t, errors.Is(err, codeUnavailable))
+
+	werr, ok := err.(*terrors.WrapError)
+	require.True(t, ok)
+	assert.Equal(t, "failed after %d attempts", werr.Template())
+	assert.Equal(t, []any{3}, werr.Args())
+	_, fn, _, _ := werr.Frame().Location()
+	assert.Equal(t, "TestRetryExhausted", fn)
+
+	attempts := werr.Unwrap().(interface{ Unwrap() []error }).Unwrap()
+	require.Len(t, attempts, 3)
+	for i, a := range attempts {
+		aerr, ok := a.(*terrors.WrapError)
+		require.True(t, ok)
+		n, ok := aerr.Field("attempt")
+		require.True(t, ok)
+		assert.Equal(t, i+1, n)
+		assert.Equal(t, "attempt %d", aerr.Template())
+		assert.Equal(t, []any{i + 1}, aerr.Args())
+		_, ok = aerr.Field("duration")
+		assert.True(t, ok)
+	}
+}
+
+func TestRetryStopsOnPermanent(t *testing.T) {
+	calls := 0
+	err := terrors.Retry(context.Background(), func(context.Context) error {
+		calls++
+		return terrors.New("bad request").WithPermanent()
+	}, terrors.RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond, RetryUnspecified: true})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryUnspecified(t *testing.T) {
+	calls := 0
+	fn := func(context.Context) error {
+		calls++
+		return io.EOF
+	}
+
+	_ = terrors.Retry(context.Background(), fn, terrors.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond})
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err := terrors.Retry(context.Background(), fn, terrors.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, RetryUnspecified: true})
+	assert.Equal(t, 3, calls)
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestRetryHonorsErrorLimits(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := terrors.Retry(context.Background(), func(context.Context) error {
+		calls++
+		return terrors.New("throttled").WithRetryAfter(20 * time.Millisecond).WithMaxAttempts(2)
+	}, terrors.RetryPolicy{MaxAttempts: 10, InitialDelay: time.Millisecond})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := terrors.Retry(ctx, func(context.Context) error {
+		calls++
+		cancel()
+		return terrors.New("slow").WithRetryable()
+	}, terrors.RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour})
+
+	assert.Equal(t, 1, calls)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, []any{1}, err.(*terrors.WrapError).Args())
+}
+
+func TestRetryJSONRoundTrip(t *testing.T) {
+	b, err := terrors.ToJSON(terrors.New("x").WithRetryAfter(1500 * time.Millisecond).WithMaxAttempts(4))
+	require.NoError(t, err)
+
+	decoded, err := terrors.FromJSON(b)
+	require.NoError(t, err)
+
+	hint := terrors.RetryHintOf(decoded)
+	assert.Equal(t, terrors.RetryHint{Retryability: terrors.RetryAllowed, After: 1500 * time.Millisecond, MaxAttempts: 4}, hint)
+}
+
+func TestRetryFingerprintStable(t *testing.T) {
+	fail := func(calls int) error {
+		n := 0
+		return terrors.Retry(context.Background(), func(context.Context) error {
+			n++
+			if n > calls {
+				return terrors.New("down").WithPermanent()
+			}
+			return terrors.New("down").WithRetryable()
+		}, terrors.RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond})
+	}
+
+	attempts := func(err error) []error {
+		return err.(*terrors.WrapError).Unwrap().(interface{ Unwrap() []error }).Unwrap()
+	}
+
+	a, b := attempts(fail(0)), attempts(fail(1))
+	assert.Equal(t, terrors.Fingerprint(a[0]), terrors.Fingerprint(b[1]))
+}
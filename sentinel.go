@@ -0,0 +1,81 @@
+package terrors
+
+import "errors"
+
+// Sentinel is a package level error definition. Unlike a terror created with
+// New at package init, it captures no frame itself: Raise, Raisef and Wrap
+// produce a fresh *WrapError framed at the call site that still matches the
+// sentinel in errors.Is and Into.
+//
+//	var ErrUserNotFound = &terrors.Sentinel{Message: "user not found", Code: CodeNotFound}
+//
+//	return ErrUserNotFound.Raise().With("user_id", id)
+type Sentinel struct {
+	Message  string
+	Code     Code
+	Recovery string
+}
+
+// NewSentinel returns a Sentinel with message.
+func NewSentinel(message string) *Sentinel {
+	return &Sentinel{Message: message}
+}
+
+func (s *Sentinel) Error() string {
+	return s.Message
+}
+
+// Raise returns a new error for s framed at the caller.
+func (s *Sentinel) Raise() *WrapError {
+	return s.raise(nil, s.Message)
+}
+
+// Raisef returns a new error for s framed at the caller, with the formatted
+// text appended to the sentinel message.
+func (s *Sentinel) Raisef(format string, a ...any) *WrapError {
+	msg, wrapped := formatMessage(format, a)
+	if msg != "" {
+		msg = s.Message + ": " + msg
+	} else {
+		msg = s.Message
+	}
+	return s.raise(wrapCause(nil, wrapped), msg).withTemplate(s.Message+": "+format, a)
+}
+
+// Wrap returns a new error for s framed at the caller, wrapping err.
+func (s *Sentinel) Wrap(err error) *WrapError {
+	return s.raise(err, s.Message)
+}
+
+func (s *Sentinel) raise(err error, message string) *WrapError {
+	we := wrapWithCaller(err, message, 2, false)
+	we.sentinel = s
+	we.code = s.Code
+	if s.Recovery != "" {
+		we.recovery = &Recovery{Suggestion: s.Recovery, Frame: we.frame}
+	}
+
+	return we
+}
+
+// Sentinel returns the sentinel e was raised from, or nil.
+func (e *WrapError) Sentinel() *Sentinel {
+	return e.sentinel
+}
+
+// As makes errors.As and Into find the sentinel an error was raised from and
+// the errors passed to Wrapf with %w.
+func (e *WrapError) As(target any) bool {
+	if t, ok := target.(**Sentinel); ok && e.sentinel != nil {
+		*t = e.sentinel
+		return true
+	}
+
+	for _, w := range e.also {
+		if errors.As(w, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package terrors_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+var errUserNotFound = &terrors.Sentinel{Message: "user not found", Code: 404, Recovery: "check the user id"}
+
+func findUser() error {
+	return errUserNotFound.Raisef("id %d", 7)
+}
+
+func TestSentinelRaise(t *testing.T) {
+	err := findUser()
+
+	assert.True(t, errors.Is(err, errUserNotFound))
+	assert.True(t, errors.Is(fmt.Errorf("handler: %w", err), errUserNotFound))
+	assert.False(t, errors.Is(terrors.New("user not found"), errUserNotFound))
+
+	s, ok := terrors.Into[*terrors.Sentinel](terrors.Wrap(err, "outer"))
+	require.True(t, ok)
+	assert.Equal(t, errUserNotFound, s)
+
+	werr := terrors.GetDeepestTerror(err)
+	require.NotNil(t, werr)
+	assert.Equal(t, errUserNotFound, werr.Sentinel())
+	assert.Equal(t, terrors.Code(404), werr.Code())
+	assert.Equal(t, "check the user id", werr.Recovery().Suggestion)
+	assert.Equal(t, []any{"user not found: id 7"}, werr.Info())
+
+	_, fn, file, _ := werr.Frame().Location()
+	assert.Equal(t, "findUser", fn)
+	assert.Equal(t, "sentinel_test.go", file)
+}
+
+func TestSentinelFreshFrames(t *testing.T) {
+	a := errUserNotFound.Raise()
+	b := errUserNotFound.Wrap(io.EOF)
+
+	assert.NotEqual(t, a.Frame(), b.Frame())
+	assert.True(t, errors.Is(b, io.EOF))
+	assert.True(t, errors.Is(b, errUserNotFound))
+	assert.Equal(t, "user not found", errUserNotFound.Error())
+}
@@ -1,16 +1,10 @@
 package terrors
 
-import (
-	"fmt"
-
-	"github.com/rs/zerolog"
-)
-
 // New returns an error that formats as the given text.
 //
 // The returned error contains a Frame set to the caller's location and
 // implements Formatter to show this information when printed with details.
-func New(text string) *wrapError {
+func New(text string) *WrapError {
 	return WrapWithCaller(nil, text, 1)
 }
 
@@ -19,16 +13,21 @@ func New(text string) *wrapError {
 //
 // The returned error contains a Frame set to the caller's location and
 // implements Formatter to show this information when printed with details.
-func Errorf(format string, a ...any) *wrapError {
-	return WrapWithCaller(nil, fmt.Sprintf(format, a...), 1)
+// Like fmt.Errorf, errors passed with %w become the wrapped cause; their text
+// is left out of the message since the chain already renders it.
+func Errorf(format string, a ...any) *WrapError {
+	msg, wrapped := formatMessage(format, a)
+	return WrapWithCaller(wrapCause(nil, wrapped), msg, 1).withTemplate(format, a)
 }
 
-func Mismatch[T any](expected, actual T) *wrapError {
-	return WrapWithCaller(nil, "mismatch", 1).Event(func(e *zerolog.Event) *zerolog.Event {
-		return e.Interface("expected", expected).Interface("actual", actual)
-	}).(*wrapError)
+func Mismatch[T any](expected, actual T) *WrapError {
+	we := WrapWithCaller(nil, "mismatch", 1)
+	we.fields = append(we.fields, Field{Key: "expected", Value: expected}, Field{Key: "actual", Value: actual})
+	return we
 }
 
-func (me *wrapError) WithMismatch(expected, actual any) *wrapError {
-	return me.With("expected", expected).With("actual", actual)
+func (me *WrapError) WithMismatch(expected, actual any) *WrapError {
+	c := me.clone()
+	c.fields = append(c.fields, Field{Key: "expected", Value: expected}, Field{Key: "actual", Value: actual})
+	return c
 }
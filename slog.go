@@ -0,0 +1,156 @@
+package terrors
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+var _ slog.LogValuer = (*WrapError)(nil)
+
+// LogValue implements slog.LogValuer, grouping the message, code, caller,
+// fields, recovery suggestion and cause chain of e.
+func (e *WrapError) LogValue() slog.Value {
+	return LogValue(e)
+}
+
+// LogValue returns the slog representation of any error. Terrors expand into
+// a group like (*WrapError).LogValue; foreign errors become a group holding
+// their own message and cause.
+func LogValue(err error) slog.Value {
+	if err == nil {
+		return slog.AnyValue(nil)
+	}
+
+	attrs := []slog.Attr{}
+
+	switch v := err.(type) {
+	case *WrapError:
+		attrs = append(attrs, slog.String("msg", v.msg))
+
+		if v.code != 0 {
+			attrs = append(attrs, slog.String("code", v.code.String()))
+		}
+
+		if pkg, function, file, line := v.frame.Location(); pkg != "" || file != "" {
+			attrs = append(attrs, slog.Group("caller",
+				slog.String("package", pkg),
+				slog.String("function", function),
+				slog.String("file", file),
+				slog.Int("line", line),
+			))
+		}
+
+		if fields := v.FieldsFor(TargetLog); len(fields) > 0 {
+			fattrs := make([]any, 0, len(fields))
+			for _, f := range fields {
+				fattrs = append(fattrs, slog.Any(f.Key, f.Value))
+			}
+			attrs = append(attrs, slog.Group("fields", fattrs...))
+		}
+
+		if v.recovery != nil {
+			attrs = append(attrs, slog.String("recovery", v.recovery.Suggestion))
+		}
+	case multiUnwrapper:
+		attrs = append(attrs, slog.String("msg", err.Error()))
+	default:
+		attrs = append(attrs, slog.String("msg", foreignMessage(err)))
+	}
+
+	kids := unwrapChildren(err)
+
+	if _, ok := err.(multiUnwrapper); ok {
+		causes := make([]any, 0, len(kids))
+		for i, kid := range kids {
+			causes = append(causes, slog.Any(strconv.Itoa(i), LogValue(kid)))
+		}
+		attrs = append(attrs, slog.Group("causes", causes...))
+	} else if len(kids) > 0 {
+		attrs = append(attrs, slog.Any("cause", LogValue(kids[0])))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// WithAttrs attaches slog attributes to the error as fields.
+func (e *WrapError) WithAttrs(attrs ...slog.Attr) *WrapError {
+	c := e.clone()
+	for _, attr := range attrs {
+		c.fields = append(c.fields, Field{Key: attr.Key, Value: slogValueAny(attr.Value)})
+	}
+	return c
+}
+
+// slogValueAny converts a slog value into a plain Go value, turning groups
+// into maps.
+func slogValueAny(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	m := map[string]any{}
+	for _, a := range v.Group() {
+		m[a.Key] = slogValueAny(a.Value)
+	}
+
+	return m
+}
+
+// SlogHandler wraps a slog.Handler so that every error attribute containing a
+// terror, even behind foreign wrappers, is expanded with LogValue.
+type SlogHandler struct {
+	handler slog.Handler
+}
+
+// NewSlogHandler wraps h with terror attribute expansion.
+func NewSlogHandler(h slog.Handler) *SlogHandler {
+	return &SlogHandler{handler: h}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(expandErrorAttr(a))
+		return true
+	})
+
+	return h.handler.Handle(ctx, expanded)
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		expanded = append(expanded, expandErrorAttr(a))
+	}
+
+	return &SlogHandler{handler: h.handler.WithAttrs(expanded)}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{handler: h.handler.WithGroup(name)}
+}
+
+func expandErrorAttr(a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	err, ok := a.Value.Any().(error)
+	if !ok {
+		return a
+	}
+
+	if _, isTerror := err.(*WrapError); !isTerror {
+		if _, found := Into[*WrapError](err); !found {
+			return a
+		}
+	}
+
+	return slog.Attr{Key: a.Key, Value: LogValue(err)}
+}
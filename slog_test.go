@@ -0,0 +1,58 @@
+package terrors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func TestSlogLogValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	inner := terrors.New("disk full").WithCode(507).WithAttrs(slog.String("volume", "/data"), slog.Group("usage", slog.Int("pct", 100)))
+	err := terrors.Wrap(inner, "saving upload").With("upload_id", "up-1").WithRecovery("free some space")
+
+	logger.Error("request failed", "err", err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	e := got["err"].(map[string]any)
+	assert.Equal(t, "saving upload", e["msg"])
+	assert.Equal(t, "free some space", e["recovery"])
+	assert.Equal(t, map[string]any{"upload_id": "up-1"}, e["fields"])
+	assert.Equal(t, "TestSlogLogValue", e["caller"].(map[string]any)["function"])
+
+	cause := e["cause"].(map[string]any)
+	assert.Equal(t, "disk full", cause["msg"])
+	assert.Equal(t, "507", cause["code"])
+	assert.Equal(t, map[string]any{"volume": "/data", "usage": map[string]any{"pct": float64(100)}}, cause["fields"])
+}
+
+func TestSlogHandlerExpandsForeignWrappers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(terrors.NewSlogHandler(slog.NewJSONHandler(buf, nil)))
+
+	err := fmt.Errorf("handler: %w", terrors.New("boom").With("k", "v"))
+
+	logger.With("static", err).Error("failed", "err", err, "plain", fmt.Errorf("no terror"))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	for _, key := range []string{"err", "static"} {
+		e, ok := got[key].(map[string]any)
+		require.True(t, ok, key)
+		assert.Equal(t, "handler", e["msg"])
+		assert.Equal(t, "boom", e["cause"].(map[string]any)["msg"])
+	}
+
+	assert.Equal(t, "no terror", got["plain"])
+}
@@ -0,0 +1,76 @@
+package terrors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth bounds how many program counters a captured stack holds.
+const maxStackDepth = 64
+
+var captureStack atomic.Bool
+
+// SetStackCapture enables or disables full stack capture for every error
+// created by this package. It is disabled by default.
+func SetStackCapture(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+// StackCaptureEnabled reports whether full stack capture is enabled globally.
+func StackCaptureEnabled() bool {
+	return captureStack.Load()
+}
+
+// callers returns the program counters on the caller's stack.
+// callers(0) starts at the caller of callers, mirroring Caller.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip+2, pcs)
+	return pcs[:n]
+}
+
+// resolveStack turns program counters into resolved Frames, expanding
+// inlined calls so every logical frame is reported.
+func resolveStack(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	out := make([]Frame, 0, len(pcs))
+	for {
+		fr, more := frames.Next()
+		out = append(out, resolvedFrame(fr))
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+// FormatStack renders frames one per line, innermost first.
+func FormatStack(frames []Frame) string {
+	lines := make([]string, 0, len(frames))
+	for _, frm := range frames {
+		pkg, function, file, line := frm.Location()
+		if pkg == "" && file == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("  at %s.%s (%s:%d)", pkg, function, file, line))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// NewWithStack is like New but always captures the full call stack.
+func NewWithStack(text string) *WrapError {
+	return wrapWithCaller(nil, text, 1, true)
+}
+
+// WrapWithStack is like Wrap but always captures the full call stack.
+func WrapWithStack(err error, message string) *WrapError {
+	return wrapWithCaller(err, message, 1, true)
+}
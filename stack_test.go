@@ -0,0 +1,45 @@
+package terrors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func deepHelper() *terrors.WrapError {
+	return terrors.NewWithStack("deep")
+}
+
+func TestStackCapture(t *testing.T) {
+	err := deepHelper()
+
+	stack := err.Stack()
+	require.GreaterOrEqual(t, len(stack), 2)
+
+	_, fn, file, _ := stack[0].Location()
+	assert.Equal(t, "deepHelper", fn)
+	assert.Equal(t, "stack_test.go", file)
+
+	_, fn, _, _ = stack[1].Location()
+	assert.Equal(t, "TestStackCapture", fn)
+
+	_, fn, _, _ = err.Frame().Location()
+	assert.Equal(t, "deepHelper", fn)
+
+	assert.Contains(t, err.Detail(), "TestStackCapture")
+	assert.Contains(t, terrors.FullChainFormatter(terrors.Wrap(err, "outer")), "TestStackCapture")
+}
+
+func TestStackCaptureGlobal(t *testing.T) {
+	assert.Empty(t, terrors.New("no stack").Stack())
+
+	terrors.SetStackCapture(true)
+	defer terrors.SetStackCapture(false)
+
+	err := terrors.Wrap(terrors.New("inner"), "outer")
+	require.NotEmpty(t, err.Stack())
+	assert.True(t, strings.Contains(terrors.FormatStack(err.Stack()), "TestStackCaptureGlobal"))
+}
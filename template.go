@@ -0,0 +1,255 @@
+package terrors
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Template returns the format string the message was built from by Errorf,
+// Wrapf and friends, or "" for errors with a literal message.
+func (e *WrapError) Template() string {
+	return e.template
+}
+
+// Args returns the arguments the message was formatted with.
+func (e *WrapError) Args() []any {
+	return e.args
+}
+
+func (e *WrapError) withTemplate(format string, a []any) *WrapError {
+	e.template = format
+	e.args = a
+	return e
+}
+
+// argsFor returns the format arguments as they should be rendered for
+// target. Errors are rendered as their message and every argument goes
+// through Redact as a field named argN; dropped arguments are masked so
+// positions are kept.
+func (e *WrapError) argsFor(target Target) []any {
+	if len(e.args) == 0 {
+		return nil
+	}
+
+	out := make([]any, 0, len(e.args))
+	for i, a := range e.args {
+		if err, ok := a.(error); ok {
+			a = err.Error()
+		}
+
+		redacted := Redact(target, []Field{{Key: "arg" + strconv.Itoa(i), Value: a}})
+		if len(redacted) == 0 {
+			out = append(out, MaskedValue)
+			continue
+		}
+		out = append(out, redacted[0].Value)
+	}
+
+	return out
+}
+
+// formatMessage formats a like fmt.Errorf but returns the errors passed with
+// %w separately instead of inlining their text, so they can become the
+// wrapped cause rather than being stringified. Operands that cannot be cut
+// cleanly from the message keep their text in it.
+func formatMessage(format string, a []any) (msg string, wrapped []error) {
+	wrapped = wrappedErrors(format, a)
+	if len(wrapped) == 0 {
+		return fmt.Sprintf(format, a...), nil
+	}
+
+	args := slices.Clone(a)
+	for _, i := range wrapOperands(format, len(args)) {
+		if _, ok := args[i].(error); ok {
+			args[i] = omitted{}
+		}
+	}
+
+	if msg, ok := dropOmitted(fmt.Sprintf(wrapVerbsAsValues(format), args...)); ok {
+		return msg, wrapped
+	}
+
+	return fmt.Sprintf(wrapVerbsAsValues(format), a...), wrapped
+}
+
+// formatWrap formats the message of Wrapf. Without err it behaves like
+// Errorf. Otherwise err stays the only cause, keeping the chain linear, and
+// the %w operands are inlined in the message and returned to be attached
+// beside it.
+func formatWrap(err error, format string, a []any) (cause error, msg string, also []error) {
+	if err == nil {
+		msg, wrapped := formatMessage(format, a)
+		return wrapCause(nil, wrapped), msg, nil
+	}
+
+	also = wrappedErrors(format, a)
+	if len(also) == 0 {
+		return err, fmt.Sprintf(format, a...), nil
+	}
+
+	return err, fmt.Sprintf(wrapVerbsAsValues(format), a...), also
+}
+
+// wrappedErrors returns the errors fmt.Errorf would wrap for format and a.
+func wrappedErrors(format string, a []any) []error {
+	if len(wrapOperands(format, len(a))) == 0 {
+		return nil
+	}
+
+	switch v := fmt.Errorf(format, a...).(type) {
+	case interface{ Unwrap() error }:
+		if w := v.Unwrap(); w != nil {
+			return []error{w}
+		}
+	case interface{ Unwrap() []error }:
+		return v.Unwrap()
+	}
+
+	return nil
+}
+
+// wrapCause combines an explicit cause with the errors passed with %w.
+func wrapCause(err error, wrapped []error) error {
+	if len(wrapped) == 0 {
+		return err
+	}
+	if err == nil && len(wrapped) == 1 {
+		return wrapped[0]
+	}
+	if err == nil {
+		return errors.Join(wrapped...)
+	}
+
+	return errors.Join(append([]error{err}, wrapped...)...)
+}
+
+// wrapOperands returns the positions in the arguments of format of the
+// operands of its %w verbs, following explicit argument indexes and the
+// arguments consumed by * widths and precisions like fmt does. Positions past
+// n are left out.
+func wrapOperands(format string, n int) []int {
+	var operands []int
+	arg := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		arg, i = argIndex(format, i, arg)
+		i, arg = skipNumber(format, i, arg)
+		if i < len(format) && format[i] == '.' {
+			arg, i = argIndex(format, i+1, arg)
+			i, arg = skipNumber(format, i, arg)
+		}
+		arg, i = argIndex(format, i, arg)
+
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		if format[i] == 'w' && arg < n {
+			operands = append(operands, arg)
+		}
+		arg++
+	}
+
+	return operands
+}
+
+// argIndex parses an explicit argument index like "[2]" at format[i],
+// returning the zero based argument it selects and the position after it.
+func argIndex(format string, i, arg int) (int, int) {
+	if i >= len(format) || format[i] != '[' {
+		return arg, i
+	}
+
+	end := strings.IndexByte(format[i:], ']')
+	if end < 0 {
+		return arg, i
+	}
+
+	n, err := strconv.Atoi(format[i+1 : i+end])
+	if err != nil || n < 1 {
+		return arg, i
+	}
+
+	return n - 1, i + end + 1
+}
+
+// skipNumber skips the width or precision at format[i]; a * consumes an
+// argument.
+func skipNumber(format string, i, arg int) (int, int) {
+	if i < len(format) && format[i] == '*' {
+		return i + 1, arg + 1
+	}
+	for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+		i++
+	}
+	return i, arg
+}
+
+// omitted replaces the arguments passed with %w. It formats as
+// omittedMarker, which dropOmitted removes again.
+type omitted struct{}
+
+const omittedMarker = "\x00"
+
+func (omitted) Format(s fmt.State, _ rune) {
+	fmt.Fprint(s, omittedMarker)
+}
+
+// dropOmitted removes the omitted %w operands from msg with cutOperand, e.g.
+// "loading: %w" becomes "loading" and "%w: retry later" becomes "retry later".
+// It reports false if an operand cannot be cut cleanly, or if operands are
+// cut from both ends, as in "%w and %w", leaving a fragment.
+func dropOmitted(msg string) (string, bool) {
+	start, end := false, false
+
+	for {
+		i := strings.Index(msg, omittedMarker)
+		if i < 0 {
+			return msg, !(start && end)
+		}
+
+		before, after := msg[:i], msg[i+len(omittedMarker):]
+		start = start || strings.Trim(before, ` ([{"'`) == ""
+		end = end || strings.Trim(after, ` )]}"'`) == ""
+
+		var ok bool
+		if msg, ok = cutOperand(before, after); !ok {
+			return "", false
+		}
+	}
+}
+
+// wrapVerbsAsValues rewrites every %w verb of format into %v.
+func wrapVerbsAsValues(format string) string {
+	b := []byte(format)
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != '%' {
+			continue
+		}
+
+		j := i + 1
+		for j < len(b) && strings.IndexByte("+-# 0123456789.*[]", b[j]) >= 0 {
+			j++
+		}
+
+		if j < len(b) && b[j] == 'w' {
+			b[j] = 'v'
+		}
+
+		i = j
+	}
+
+	return string(b)
+}
@@ -0,0 +1,131 @@
+package terrors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func TestTemplateAndArgs(t *testing.T) {
+	err := terrors.Errorf("user %s has %d items", "bob", 3)
+
+	assert.Equal(t, "user %s has %d items", err.Template())
+	assert.Equal(t, []any{"bob", 3}, err.Args())
+	assert.Equal(t, []any{"user bob has 3 items"}, err.Info())
+	assert.Contains(t, err.Detail(), "args     = [bob 3]")
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, "user %s has %d items", got["template"])
+	assert.Equal(t, []any{"bob", float64(3)}, got["args"])
+
+	decoded, jerr := terrors.FromJSON(b)
+	require.NoError(t, jerr)
+	assert.Equal(t, "user %s has %d items", decoded.(*terrors.WrapError).Template())
+
+	assert.Equal(t, "", terrors.New("literal").Template())
+	assert.Nil(t, terrors.New("literal").Args())
+}
+
+func TestErrorfWrapVerb(t *testing.T) {
+	err := terrors.Errorf("reading %s: %w", "config.yaml", io.EOF)
+
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.Equal(t, io.EOF, err.Unwrap())
+	assert.Equal(t, []any{"reading config.yaml"}, err.Info())
+	assert.Equal(t, "reading %s: %w", err.Template())
+	assert.Equal(t, []any{"config.yaml", io.EOF}, err.Args())
+	assert.NotContains(t, err.Detail(), "message  = reading config.yaml: EOF")
+
+	literal := terrors.Errorf("100%% done %w", io.EOF)
+	assert.Equal(t, []any{"100% done"}, literal.Info())
+
+	for format, want := range map[string]string{
+		"key: %w":            "key",
+		"%w: retry later":    "retry later",
+		"  padded: %w":       "  padded",
+		"%s: %w":             "key:",
+		"before %w after: ":  "before EOF after: ",
+		"failed (%w)":        "failed",
+		"%w (while reading)": "(while reading)",
+		"a %w, b %[1]w":      "a EOF, b EOF",
+	} {
+		args := []any{io.EOF}
+		if strings.HasPrefix(format, "%s") {
+			args = []any{"key:", io.EOF}
+		}
+		assert.Equal(t, []any{want}, terrors.Errorf(format, args...).Info(), format)
+	}
+
+	other := errors.New("other")
+	multi := terrors.Errorf("%w and %w", io.EOF, other)
+	assert.True(t, errors.Is(multi, io.EOF))
+	assert.True(t, errors.Is(multi, other))
+	assert.Equal(t, []any{"EOF and other"}, multi.Info())
+
+	unc := terrors.Errorf("op: %w", errorUncomparable{})
+	assert.Equal(t, []any{"op"}, unc.Info())
+	assert.True(t, errors.Is(unc, errorUncomparable{}))
+
+	indexed := terrors.Errorf("%[2]s: %[1]w", io.EOF, "key")
+	assert.Equal(t, []any{"key"}, indexed.Info())
+
+	star := terrors.Errorf("%*d: %w", 3, 7, io.EOF)
+	assert.Equal(t, []any{"  7"}, star.Info())
+}
+
+func TestWrapfWrapVerb(t *testing.T) {
+	base := errors.New("base")
+	err := terrors.Wrapf(base, "retry %d failed: %w", 2, io.ErrUnexpectedEOF)
+
+	assert.True(t, errors.Is(err, base))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.Equal(t, base, err.Unwrap())
+	assert.Equal(t, []any{"retry 2 failed: unexpected EOF"}, err.Info())
+
+	var pathErr *fs.PathError
+	withPath := terrors.Wrapf(base, "open: %w", &fs.PathError{Op: "open", Path: "x", Err: io.EOF})
+	require.True(t, errors.As(withPath, &pathErr))
+	assert.Equal(t, "x", pathErr.Path)
+
+	inner := terrors.Wrap(base, "inner")
+	outer := terrors.Wrapf(inner, "outer: %w", io.ErrUnexpectedEOF)
+	assert.Len(t, terrors.GetChain(outer), 3)
+	assert.Same(t, inner, terrors.GetDeepestTerror(outer))
+	causes, _ := terrors.ListCause(outer)
+	assert.Len(t, causes, 2)
+
+	plain := terrors.Wrapf(base, "value %v", io.EOF)
+	assert.Equal(t, base, plain.Unwrap())
+	assert.Equal(t, []any{"value EOF"}, plain.Info())
+}
+
+func TestTemplateArgsNotEncodable(t *testing.T) {
+	ch := make(chan int)
+	err := terrors.Errorf("cb %v on %v with %d", func() {}, ch, 3)
+
+	b, jerr := json.Marshal(err)
+	require.NoError(t, jerr)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(b, &got))
+	args := got["args"].([]any)
+	require.Len(t, args, 3)
+	assert.IsType(t, "", args[0])
+	assert.Equal(t, fmt.Sprint(ch), args[1])
+	assert.Equal(t, float64(3), args[2])
+
+	assert.Len(t, err.Args(), 3)
+	assert.IsType(t, ch, err.Args()[1])
+}
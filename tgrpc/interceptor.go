@@ -0,0 +1,78 @@
+package tgrpc
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+)
+
+// serverError converts an error returned by a handler into a status error.
+func serverError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return ToStatus(err).Err()
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers into gRPC
+// statuses using ToStatus.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, serverError(err)
+	}
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers into
+// gRPC statuses using ToStatus.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return serverError(handler(srv, ss))
+	}
+}
+
+// UnaryClientInterceptor converts status errors returned by unary calls back
+// into terrors using FromError.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor converts status errors returned by streaming calls
+// back into terrors using FromError. io.EOF is passed through untouched.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromError(err)
+		}
+		return &clientStream{cs}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func clientError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	return FromError(err)
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return clientError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return clientError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return clientError(s.ClientStream.CloseSend())
+}
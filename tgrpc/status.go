@@ -0,0 +1,238 @@
+// Package tgrpc converts terrors to and from gRPC statuses and provides
+// interceptors that do it automatically.
+package tgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/walteh/terrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorInfoDomain is the domain set on the ErrorInfo detail of statuses
+// produced by ToStatus.
+const ErrorInfoDomain = "terrors"
+
+// ToGRPCCode maps a terror code to a gRPC code. Registered codes use their
+// CodeInfo.GRPCCode, unregistered codes 1 through 16 are used as gRPC codes as
+// is, and everything else is Unknown. Replace it during program initialization
+// to use a different mapping.
+var ToGRPCCode = func(code terrors.Code) codes.Code {
+	if info, ok := code.Info(); ok && info.GRPCCode != 0 {
+		return codes.Code(info.GRPCCode)
+	}
+	if code > 0 && code <= terrors.Code(codes.Unauthenticated) {
+		return codes.Code(code)
+	}
+	return codes.Unknown
+}
+
+// FromGRPCCode maps a gRPC code back to a terror code. It is the inverse of
+// the default ToGRPCCode for unregistered codes.
+var FromGRPCCode = func(c codes.Code) terrors.Code {
+	return terrors.Code(c)
+}
+
+// StatusError is the error produced by FromStatus. It unwraps to the decoded
+// terror and still reports the original status to status.FromError.
+type StatusError struct {
+	status *status.Status
+	err    error
+}
+
+func (e *StatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the status the error was decoded from.
+func (e *StatusError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// ToStatus converts err to a gRPC status. The code comes from the outermost
+// terror with a code, and the details carry the caller location and fields
+// (ErrorInfo), the recovery suggestion (LocalizedMessage), the retry delay
+// (RetryInfo) and the serialized chain (DebugInfo). Like the message, the
+// serialized chain leaves out foreign errors, and fields are redacted for
+// terrors.TargetClient.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	top, ok := terrors.FirstCause(err)
+	if !ok {
+		return status.Convert(err)
+	}
+
+	st := status.New(grpcCode(err), terrorMessage(err))
+
+	info := &errdetails.ErrorInfo{
+		Reason:   terrors.CodeOf(err).String(),
+		Domain:   ErrorInfoDomain,
+		Metadata: map[string]string{},
+	}
+
+	for _, f := range terrors.Redact(terrors.TargetClient, terrors.AllFields(err)) {
+		info.Metadata[f.Key] = fmt.Sprint(f.Value)
+	}
+
+	debug := &errdetails.DebugInfo{}
+	if b, jerr := terrors.ToJSONFor(err, terrors.TargetClient); jerr == nil {
+		debug.Detail = string(b)
+	}
+
+	pkg, function, file, line := top.Frame().Location()
+	info.Metadata["package"] = pkg
+	info.Metadata["function"] = function
+	info.Metadata["file"] = file
+	info.Metadata["line"] = strconv.Itoa(line)
+
+	for _, link := range terrors.GetChainLinks(err) {
+		if werr, ok := link.Err.(*terrors.WrapError); ok {
+			lpkg, lfunc, lfile, lline := werr.Frame().Location()
+			debug.StackEntries = append(debug.StackEntries, fmt.Sprintf("%s.%s %s:%d", lpkg, lfunc, lfile, lline))
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+
+	if ok, rec := terrors.IsRecoverable(err); ok && rec.Suggestion != "" {
+		details = append(details, &errdetails.LocalizedMessage{Locale: "en-US", Message: rec.Suggestion})
+	}
+
+	if d, ok := terrors.RetryAfter(err); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(d)})
+	}
+
+	details = append(details, debug)
+
+	withDetails, derr := st.WithDetails(details...)
+	if derr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromStatus rebuilds an error from a status produced by ToStatus. Statuses
+// produced elsewhere become a terror holding the status message and code.
+// A nil or OK status returns nil.
+func FromStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	var decoded error
+	var info *errdetails.ErrorInfo
+	var suggestion string
+	var retryDelay time.Duration
+
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.DebugInfo:
+			if v.Detail != "" {
+				if derr, jerr := terrors.FromJSON([]byte(v.Detail)); jerr == nil {
+					decoded = derr
+				}
+			}
+		case *errdetails.ErrorInfo:
+			if v.Domain == ErrorInfoDomain {
+				info = v
+			}
+		case *errdetails.LocalizedMessage:
+			suggestion = v.Message
+		case *errdetails.RetryInfo:
+			retryDelay = v.GetRetryDelay().AsDuration()
+		}
+	}
+
+	if decoded == nil {
+		frame := terrors.RemoteFrame{}
+		if info != nil {
+			line, _ := strconv.Atoi(info.Metadata["line"])
+			frame = terrors.RemoteFrame{
+				Package:  info.Metadata["package"],
+				Function: info.Metadata["function"],
+				File:     info.Metadata["file"],
+				Line:     line,
+			}
+		}
+
+		rebuilt := terrors.WrapWithFrame(nil, st.Message(), frame.Frame()).WithCode(FromGRPCCode(st.Code()))
+		if suggestion != "" {
+			rebuilt = rebuilt.WithRecovery(suggestion)
+		}
+		if retryDelay > 0 {
+			rebuilt = rebuilt.WithRetryAfter(retryDelay)
+		}
+		decoded = rebuilt
+	}
+
+	return &StatusError{status: st, err: decoded}
+}
+
+// FromError converts an error returned by a gRPC call back into a terror. It
+// returns err unchanged if it does not carry a status.
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := terrors.Into[*StatusError](err); ok {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return FromStatus(st)
+}
+
+func grpcCode(err error) codes.Code {
+	if code := terrors.CodeOf(err); code != 0 {
+		return ToGRPCCode(code)
+	}
+
+	if se, ok := terrors.Into[*StatusError](err); ok {
+		return se.status.Code()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+
+	return codes.Unknown
+}
+
+// terrorMessage joins the messages of the terror links of the chain. Foreign
+// messages are left out: they may carry anything, and the status reaches
+// clients.
+func terrorMessage(err error) string {
+	msgs := []string{}
+	for _, link := range terrors.GetChainLinks(err) {
+		if link.Kind == terrors.LinkTerror {
+			msgs = append(msgs, link.Message)
+		}
+	}
+
+	return strings.Join(msgs, ": ")
+}
@@ -0,0 +1,189 @@
+package tgrpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+	"github.com/walteh/terrors/tgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type failingHealth struct {
+	healthpb.UnimplementedHealthServer
+	err error
+}
+
+func (h *failingHealth) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return nil, h.err
+}
+
+func (h *failingHealth) Watch(_ *healthpb.HealthCheckRequest, _ healthpb.Health_WatchServer) error {
+	return h.err
+}
+
+func dial(t *testing.T, handlerErr error) healthpb.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(tgrpc.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(tgrpc.StreamServerInterceptor()),
+	)
+	healthpb.RegisterHealthServer(srv, &failingHealth{err: handlerErr})
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tgrpc.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tgrpc.StreamClientInterceptor()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestToStatusRoundTrip(t *testing.T) {
+	err := terrors.Wrap(errors.New("row missing"), "lookup user").
+		WithCode(terrors.Code(codes.NotFound)).
+		With("user_id", "u-1").
+		WithRecovery("create the user first")
+
+	st := tgrpc.ToStatus(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "lookup user", st.Message())
+	require.Len(t, st.Details(), 3)
+
+	back := tgrpc.FromStatus(st)
+	assert.Equal(t, codes.NotFound, status.Code(back))
+	assert.NotContains(t, back.Error(), "row missing")
+
+	werr := terrors.GetDeepestTerror(back)
+	require.NotNil(t, werr)
+	assert.Equal(t, terrors.Code(codes.NotFound), werr.Code())
+
+	ok, info := terrors.IsRecoverable(back)
+	require.True(t, ok)
+	assert.Equal(t, "create the user first", info.Suggestion)
+
+	_, fn, file, _ := werr.Frame().Location()
+	assert.Equal(t, "TestToStatusRoundTrip", fn)
+	assert.Equal(t, "tgrpc_test.go", file)
+}
+
+func TestFromStatusForeign(t *testing.T) {
+	err := tgrpc.FromStatus(status.New(codes.PermissionDenied, "nope"))
+
+	werr := terrors.GetDeepestTerror(err)
+	require.NotNil(t, werr)
+	assert.Equal(t, terrors.Code(codes.PermissionDenied), werr.Code())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	assert.Nil(t, tgrpc.FromStatus(status.New(codes.OK, "")))
+}
+
+func TestContextErrorCodes(t *testing.T) {
+	st := tgrpc.ToStatus(terrors.Wrap(context.DeadlineExceeded, "waiting"))
+	assert.Equal(t, codes.DeadlineExceeded, st.Code())
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	client := dial(t, terrors.New("backend down").WithCode(terrors.Code(codes.Unavailable)).WithRecovery("try again later"))
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Error(t, err)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	werr := terrors.GetDeepestTerror(err)
+	require.NotNil(t, werr)
+	assert.Equal(t, terrors.Code(codes.Unavailable), werr.Code())
+
+	_, fn, _, _ := werr.Frame().Location()
+	assert.Equal(t, "TestUnaryInterceptors", fn)
+
+	ok, info := terrors.IsRecoverable(err)
+	require.True(t, ok)
+	assert.Equal(t, "try again later", info.Suggestion)
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	client := dial(t, terrors.New("no watchers").WithCode(terrors.Code(codes.ResourceExhausted)))
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	werr := terrors.GetDeepestTerror(err)
+	require.NotNil(t, werr)
+	assert.Equal(t, "no watchers", werr.Info()[0])
+}
+
+func TestRetryInfo(t *testing.T) {
+	st := tgrpc.ToStatus(terrors.New("busy").WithCode(terrors.Code(codes.Unavailable)).WithRetryAfter(2 * time.Second))
+
+	var found bool
+	for _, d := range st.Details() {
+		if ri, ok := d.(*errdetails.RetryInfo); ok {
+			found = true
+			assert.Equal(t, 2*time.Second, ri.GetRetryDelay().AsDuration())
+		}
+	}
+	assert.True(t, found)
+
+	d, ok := terrors.RetryAfter(tgrpc.FromStatus(st))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, d)
+
+	foreign, err := status.New(codes.Unavailable, "busy").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	require.NoError(t, err)
+	d, ok = terrors.RetryAfter(tgrpc.FromStatus(foreign))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+}
+
+func TestToStatusPanicFields(t *testing.T) {
+	var err error
+	func() {
+		defer terrors.Recover(&err)
+		panic("secret=abc")
+	}()
+
+	st := tgrpc.ToStatus(err)
+	assert.Equal(t, "panic", st.Message())
+	for _, d := range st.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			assert.NotContains(t, v.Metadata, terrors.PanicStackField)
+			assert.NotContains(t, v.Metadata, terrors.PanicValueField)
+		case *errdetails.DebugInfo:
+			assert.NotContains(t, v.Detail, "goroutine")
+			assert.NotContains(t, v.Detail, "secret=abc")
+		}
+	}
+}
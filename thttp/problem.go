@@ -0,0 +1,165 @@
+// Package thttp writes terrors as RFC 9457 problem details and recovers
+// panics in net/http handlers.
+package thttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/walteh/terrors"
+)
+
+// ContentType is the media type of problem detail responses.
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	Type     string         `json:"type,omitempty"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     int            `json:"code,omitempty"`
+	CodeName string         `json:"code_name,omitempty"`
+	Recovery string         `json:"recovery,omitempty"`
+	Fields   map[string]any `json:"fields,omitempty"`
+}
+
+// Writer converts errors into problem detail responses.
+type Writer struct {
+	// SafeFields lists the attached fields that may be copied to responses.
+	// Every other field stays internal.
+	SafeFields []string
+	// StatusCode maps a terror code to an HTTP status. Defaults to
+	// DefaultStatusCode.
+	StatusCode func(code terrors.Code) int
+	// OnError, if set, is called with every error before it is written.
+	OnError func(r *http.Request, err error)
+}
+
+// DefaultWriter is used by the package level functions.
+var DefaultWriter = &Writer{}
+
+// DefaultStatusCode uses the CodeInfo.HTTPStatus of registered codes, uses
+// unregistered codes in the 4xx and 5xx range as HTTP statuses, and maps
+// everything else to 500.
+func DefaultStatusCode(code terrors.Code) int {
+	if info, ok := code.Info(); ok && info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	if code >= 400 && code <= 599 {
+		return int(code)
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError writes err using DefaultWriter.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultWriter.WriteError(w, r, err)
+}
+
+// Recoverer recovers panics using DefaultWriter.
+func Recoverer(next http.Handler) http.Handler {
+	return DefaultWriter.Recoverer(next)
+}
+
+// WriteError writes err as an application/problem+json response, with a
+// Retry-After header if err requests a retry delay. Nothing is written for a
+// nil error.
+func (wr *Writer) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+
+	if wr.OnError != nil {
+		wr.OnError(r, err)
+	}
+
+	p := wr.Problem(r, err)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if d, ok := terrors.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Problem builds the problem details for err. Only the messages of terrors in
+// the chain, the recovery suggestion and SafeFields are exposed, with fields
+// redacted for terrors.TargetClient; frames and foreign error messages are
+// not.
+func (wr *Writer) Problem(r *http.Request, err error) *Problem {
+	code := terrors.CodeOf(err)
+
+	statusCode := DefaultStatusCode
+	if wr.StatusCode != nil {
+		statusCode = wr.StatusCode
+	}
+
+	p := &Problem{
+		Status: statusCode(code),
+		Code:   int(code),
+	}
+	if info, ok := code.Info(); ok {
+		p.CodeName = info.Name
+	}
+	p.Title = http.StatusText(p.Status)
+
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+
+	msgs := []string{}
+	for _, link := range terrors.GetChainLinks(err) {
+		if link.Kind == terrors.LinkTerror {
+			msgs = append(msgs, link.Message)
+		}
+	}
+	p.Detail = strings.Join(msgs, ": ")
+
+	if ok, info := terrors.IsRecoverable(err); ok {
+		p.Recovery = info.Suggestion
+	}
+
+	if len(wr.SafeFields) > 0 {
+		for _, f := range terrors.Redact(terrors.TargetClient, terrors.AllFields(err)) {
+			if slices.Contains(wr.SafeFields, f.Key) {
+				if p.Fields == nil {
+					p.Fields = map[string]any{}
+				}
+				p.Fields[f.Key] = f.Value
+			}
+		}
+	}
+
+	return p
+}
+
+// Recoverer returns middleware that converts panics in next into terrors,
+// framed at the panic site, and writes them as problem details.
+// http.ErrAbortHandler is re-panicked as net/http expects.
+func (wr *Writer) Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			err := terrors.FromPanic(v).With("request", fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+			wr.WriteError(w, r, err)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
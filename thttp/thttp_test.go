@@ -0,0 +1,139 @@
+package thttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+	"github.com/walteh/terrors/thttp"
+)
+
+func TestWriteError(t *testing.T) {
+	err := terrors.Wrap(fmt.Errorf("select failed: %w", errors.New("pq: relation users does not exist")), "user not found").
+		WithCode(http.StatusNotFound).
+		With("user_id", "u-1").
+		With("sql", "SELECT * FROM users").
+		WithRecovery("check the user id")
+
+	wr := &thttp.Writer{SafeFields: []string{"user_id"}}
+
+	rec := httptest.NewRecorder()
+	wr.WriteError(rec, httptest.NewRequest(http.MethodGet, "/users/u-1", nil), err)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, thttp.ContentType, rec.Header().Get("Content-Type"))
+
+	var p thttp.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+
+	assert.Equal(t, thttp.Problem{
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "user not found",
+		Instance: "/users/u-1",
+		Code:     http.StatusNotFound,
+		Recovery: "check the user id",
+		Fields:   map[string]any{"user_id": "u-1"},
+	}, p)
+
+	assert.NotContains(t, rec.Body.String(), "thttp_test.go")
+	assert.NotContains(t, rec.Body.String(), "pq:")
+}
+
+func TestWriteErrorDefaults(t *testing.T) {
+	rec := httptest.NewRecorder()
+	thttp.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), errors.New("plain"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var p thttp.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "", p.Detail)
+	assert.Nil(t, p.Fields)
+}
+
+func explode() {
+	var m map[string]int
+	m["boom"]++
+}
+
+func TestRecoverer(t *testing.T) {
+	var got error
+	wr := &thttp.Writer{OnError: func(_ *http.Request, err error) { got = err }}
+
+	h := wr.Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		explode()
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/explode", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	werr, ok := terrors.Into[*terrors.WrapError](got)
+	require.True(t, ok)
+
+	_, fn, file, _ := werr.Frame().Location()
+	assert.Equal(t, "explode", fn)
+	assert.Equal(t, "thttp_test.go", file)
+
+	var rerr interface{ RuntimeError() }
+	assert.True(t, errors.As(got, &rerr))
+	assert.True(t, errors.Is(got, terrors.CodePanic))
+}
+
+func TestRecovererAbort(t *testing.T) {
+	h := thttp.Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+var codeConflict = terrors.RegisterCode(9101, terrors.CodeInfo{Name: "VERSION_CONFLICT", HTTPStatus: http.StatusConflict})
+
+func TestWriteErrorRegisteredCode(t *testing.T) {
+	rec := httptest.NewRecorder()
+	thttp.WriteError(rec, httptest.NewRequest(http.MethodPut, "/doc", nil), terrors.New("stale version").WithCode(codeConflict))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	var p thttp.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, 9101, p.Code)
+	assert.Equal(t, "VERSION_CONFLICT", p.CodeName)
+}
+
+func TestWriteErrorRetryAfter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	thttp.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil),
+		terrors.New("slow down").WithCode(terrors.Code(http.StatusTooManyRequests)).WithRetryAfter(1500*time.Millisecond))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	thttp.WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), terrors.New("boom"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
+
+func TestRecovererHidesPanicValue(t *testing.T) {
+	h := thttp.Recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("db password=hunter2")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "hunter2")
+}
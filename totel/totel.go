@@ -0,0 +1,169 @@
+// Package totel records terrors on OpenTelemetry spans.
+//
+// Importing it also registers context fields so that every terror created
+// with terrors.NewCtx, terrors.WrapCtx and friends carries the trace and span
+// IDs active in that context.
+package totel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/walteh/terrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDField is the error field holding the active trace ID.
+	TraceIDField = "trace_id"
+	// SpanIDField is the error field holding the active span ID.
+	SpanIDField = "span_id"
+
+	// LinkEventName is the name of the span event recorded for every link
+	// of the error chain.
+	LinkEventName = "terror.link"
+)
+
+const (
+	codeKey      = attribute.Key("terror.code")
+	codeNameKey  = attribute.Key("terror.code_name")
+	recoveryKey  = attribute.Key("terror.recovery")
+	fieldPrefix  = "terror.field."
+	linkIndexKey = attribute.Key("terror.link.index")
+	linkKindKey  = attribute.Key("terror.link.kind")
+	linkMsgKey   = attribute.Key("terror.link.message")
+)
+
+func init() {
+	terrors.RegisterContextField(TraceIDField, func(ctx context.Context) (any, bool) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.HasTraceID() {
+			return nil, false
+		}
+		return sc.TraceID().String(), true
+	})
+
+	terrors.RegisterContextField(SpanIDField, func(ctx context.Context) (any, bool) {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.HasSpanID() {
+			return nil, false
+		}
+		return sc.SpanID().String(), true
+	})
+}
+
+// RecordError records err on span: the span status is set to error, an
+// "exception" event carries the message, the frames as exception.stacktrace,
+// the code and every attached field, and one LinkEventName event is added per
+// link of the chain. Nothing happens for a nil error or a non-recording span.
+func RecordError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	msg := message(err)
+
+	span.SetStatus(codes.Error, msg)
+
+	attrs := []attribute.KeyValue{
+		semconv.ExceptionType(fmt.Sprintf("%T", terrors.GetDeepest(err))),
+		semconv.ExceptionMessage(msg),
+	}
+
+	if st := stacktrace(err); st != "" {
+		attrs = append(attrs, semconv.ExceptionStacktrace(st))
+	}
+
+	if code := terrors.CodeOf(err); code != 0 {
+		attrs = append(attrs, codeKey.Int(int(code)))
+		if info, ok := code.Info(); ok {
+			attrs = append(attrs, codeNameKey.String(info.Name))
+		}
+	}
+
+	if ok, info := terrors.IsRecoverable(err); ok {
+		attrs = append(attrs, recoveryKey.String(info.Suggestion))
+	}
+
+	for _, f := range terrors.Redact(terrors.TargetLog, terrors.AllFields(err)) {
+		attrs = append(attrs, fieldAttribute(fieldPrefix+f.Key, f.Value))
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, append(opts, trace.WithAttributes(attrs...))...)
+
+	for i, link := range terrors.GetChainLinks(err) {
+		lattrs := []attribute.KeyValue{
+			linkIndexKey.Int(i),
+			linkKindKey.String(link.Kind.String()),
+			linkMsgKey.String(link.Message),
+		}
+
+		if werr, ok := link.Err.(*terrors.WrapError); ok {
+			pkg, function, file, line := werr.Frame().Location()
+			lattrs = append(lattrs,
+				semconv.CodeNamespace(pkg),
+				semconv.CodeFunction(function),
+				semconv.CodeFilepath(file),
+				semconv.CodeLineNumber(line),
+			)
+			if werr.Code() != 0 {
+				lattrs = append(lattrs, codeKey.Int(int(werr.Code())))
+			}
+		}
+
+		span.AddEvent(LinkEventName, append(opts, trace.WithAttributes(lattrs...))...)
+	}
+}
+
+// message joins the messages of every link of the chain.
+func message(err error) string {
+	links := terrors.GetChainLinks(err)
+	msgs := make([]string, 0, len(links))
+	for _, link := range links {
+		msgs = append(msgs, link.Message)
+	}
+
+	return strings.Join(msgs, ": ")
+}
+
+// stacktrace renders the captured stack of the deepest terror when there is
+// one, and otherwise the frame of every terror in the chain.
+func stacktrace(err error) string {
+	if deepest := terrors.GetDeepestTerror(err); deepest != nil {
+		if stack := deepest.Stack(); len(stack) > 0 {
+			return terrors.FormatStack(stack)
+		}
+	}
+
+	frames := []terrors.Frame{}
+	for _, link := range terrors.GetChainLinks(err) {
+		if werr, ok := link.Err.(*terrors.WrapError); ok {
+			frames = append(frames, werr.Frame())
+		}
+	}
+
+	return terrors.FormatStack(frames)
+}
+
+func fieldAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	}
+
+	return attribute.String(key, fmt.Sprint(value))
+}
@@ -0,0 +1,86 @@
+package totel_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+	"github.com/walteh/terrors/totel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func attrs(kvs []attribute.KeyValue) map[string]any {
+	out := map[string]any{}
+	for _, kv := range kvs {
+		out[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return out
+}
+
+func TestRecordError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+
+	inner := terrors.NewCtx(ctx, "disk full").WithCode(507).With("volume", "/data").WithRecovery("free space")
+	err := terrors.Wrap(fmt.Errorf("saving: %w", inner), "upload")
+
+	totel.RecordError(span, err)
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+
+	got := spans[0]
+	assert.Equal(t, codes.Error, got.Status.Code)
+	assert.Equal(t, "upload: saving: disk full", got.Status.Description)
+
+	require.Len(t, got.Events, 4)
+
+	exc := attrs(got.Events[0].Attributes)
+	assert.Equal(t, "exception", got.Events[0].Name)
+	assert.Equal(t, "upload: saving: disk full", exc["exception.message"])
+	assert.Equal(t, "*terrors.WrapError", exc["exception.type"])
+	assert.Contains(t, exc["exception.stacktrace"], "TestRecordError (totel_test.go:")
+	assert.Equal(t, int64(507), exc["terror.code"])
+	assert.Equal(t, "free space", exc["terror.recovery"])
+	assert.Equal(t, "/data", exc["terror.field.volume"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), exc["terror.field.trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), exc["terror.field.span_id"])
+
+	kinds := []any{}
+	for _, ev := range got.Events[1:] {
+		assert.Equal(t, totel.LinkEventName, ev.Name)
+		kinds = append(kinds, attrs(ev.Attributes)["terror.link.kind"])
+	}
+	assert.Equal(t, []any{"terror", "foreign", "terror"}, kinds)
+
+	last := attrs(got.Events[3].Attributes)
+	assert.Equal(t, "disk full", last["terror.link.message"])
+	assert.Equal(t, "TestRecordError", last["code.function"])
+	assert.Equal(t, "totel_test.go", last["code.filepath"])
+	assert.Equal(t, int64(507), last["terror.code"])
+}
+
+func TestTraceIDsInDetail(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	err := terrors.NewCtx(ctx, "boom")
+
+	traceID, ok := err.Field(totel.TraceIDField)
+	require.True(t, ok)
+	assert.Equal(t, span.SpanContext().TraceID().String(), traceID)
+	assert.Contains(t, err.Detail(), span.SpanContext().TraceID().String())
+
+	_, ok = terrors.NewCtx(context.Background(), "no span").Field(totel.TraceIDField)
+	assert.False(t, ok)
+}
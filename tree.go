@@ -0,0 +1,144 @@
+package terrors
+
+// Tree is one node of an error tree. Joined errors (anything implementing
+// Unwrap() []error) produce one child per branch.
+type Tree struct {
+	Err      error
+	Depth    int
+	Children []*Tree
+}
+
+type multiUnwrapper interface {
+	Unwrap() []error
+}
+
+type unwrapper interface {
+	Unwrap() error
+}
+
+// unwrapChildren returns the errors directly below err in the tree, following
+// the standard Unwrap protocol. Errors without an Unwrap method, such as the
+// ones returned by errors.Opaque, are deliberate boundaries.
+func unwrapChildren(err error) []error {
+	switch v := err.(type) {
+	case *WrapError:
+		if v.err != nil {
+			return []error{v.err}
+		}
+	case multiUnwrapper:
+		kids := []error{}
+		for _, k := range v.Unwrap() {
+			if k != nil {
+				kids = append(kids, k)
+			}
+		}
+		return kids
+	case unwrapper:
+		if kid := v.Unwrap(); kid != nil {
+			return []error{kid}
+		}
+	}
+
+	return nil
+}
+
+// Walk visits err and every error below it depth-first, following every
+// branch of joined errors. Returning false from fn stops the walk.
+func Walk(err error, fn func(err error, depth int) bool) {
+	walk(err, 0, fn)
+}
+
+func walk(err error, depth int, fn func(err error, depth int) bool) bool {
+	if err == nil {
+		return true
+	}
+
+	if !fn(err, depth) {
+		return false
+	}
+
+	for _, kid := range unwrapChildren(err) {
+		if !walk(kid, depth+1, fn) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTree builds the full error tree rooted at err.
+func GetTree(err error) *Tree {
+	if err == nil {
+		return nil
+	}
+
+	return buildTree(err, 0)
+}
+
+func buildTree(err error, depth int) *Tree {
+	node := &Tree{Err: err, Depth: depth}
+	for _, kid := range unwrapChildren(err) {
+		node.Children = append(node.Children, buildTree(kid, depth+1))
+	}
+
+	return node
+}
+
+// GetAll is the tree-aware version of GetChain: it returns every error in the
+// tree in depth-first order.
+func GetAll(err error) []error {
+	errs := []error{}
+	Walk(err, func(e error, _ int) bool {
+		errs = append(errs, e)
+		return true
+	})
+
+	return errs
+}
+
+// GetLeaves is the tree-aware version of GetDeepest: it returns the deepest
+// error of every branch.
+func GetLeaves(err error) []error {
+	errs := []error{}
+	Walk(err, func(e error, _ int) bool {
+		if len(unwrapChildren(e)) == 0 {
+			errs = append(errs, e)
+		}
+		return true
+	})
+
+	return errs
+}
+
+// GetDeepestTerrors is the tree-aware version of GetDeepestTerror: it returns
+// the deepest *WrapError of every branch, without duplicates.
+func GetDeepestTerrors(err error) []*WrapError {
+	out := []*WrapError{}
+	seen := map[*WrapError]bool{}
+
+	var visit func(e error, current *WrapError)
+	visit = func(e error, current *WrapError) {
+		if we, ok := e.(*WrapError); ok {
+			current = we
+		}
+
+		kids := unwrapChildren(e)
+		if len(kids) == 0 {
+			if current != nil && !seen[current] {
+				seen[current] = true
+				out = append(out, current)
+			}
+			return
+		}
+
+		for _, kid := range kids {
+			visit(kid, current)
+		}
+	}
+
+	if err != nil {
+		visit(err, nil)
+	}
+
+	return out
+}
@@ -0,0 +1,64 @@
+package terrors_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/walteh/terrors"
+)
+
+func TestTreeTraversal(t *testing.T) {
+	left := terrors.New("left")
+	rightRoot := errors.New("right root")
+	right := terrors.Wrap(rightRoot, "right").WithRecovery("retry the right side")
+	joined := errors.Join(left, right)
+	top := terrors.Wrap(joined, "top")
+
+	all := terrors.GetAll(top)
+	assert.Equal(t, []error{top, joined, left, right, rightRoot}, all)
+
+	assert.Equal(t, []error{left, rightRoot}, terrors.GetLeaves(top))
+
+	deepest := terrors.GetDeepestTerrors(top)
+	require.Len(t, deepest, 2)
+	assert.Equal(t, left, deepest[0])
+	assert.Equal(t, right, deepest[1])
+
+	tree := terrors.GetTree(top)
+	require.Len(t, tree.Children, 1)
+	require.Len(t, tree.Children[0].Children, 2)
+	assert.Equal(t, 2, tree.Children[0].Children[1].Depth)
+
+	assert.Len(t, terrors.ListAllCauses(top), 3)
+
+	ok, info := terrors.IsRecoverable(top)
+	require.True(t, ok)
+	assert.Equal(t, "retry the right side", info.Suggestion)
+}
+
+func TestWalkStops(t *testing.T) {
+	err := terrors.Wrap(errors.Join(terrors.New("a"), terrors.New("b")), "top")
+
+	count := 0
+	terrors.Walk(err, func(e error, depth int) bool {
+		count++
+		return depth < 1
+	})
+
+	assert.Equal(t, 2, count)
+}
+
+func TestJoinedFormatting(t *testing.T) {
+	err := terrors.Wrap(errors.Join(terrors.New("first"), errors.New("second")), "top")
+
+	inline := err.Error()
+	assert.Contains(t, inline, " -> [ ERROR[msg=first]")
+	assert.Contains(t, inline, " | second ]")
+
+	full := terrors.FullChainFormatter(err)
+	assert.Contains(t, full, "+ joined 2 errors")
+	assert.True(t, strings.Contains(full, "    x second"), full)
+}
@@ -6,130 +6,210 @@ package terrors
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/rs/zerolog"
 )
 
-type wrapError struct {
-	msg      string
+// TError is the read-only contract implemented by every error built by this
+// package. Downstream code and mocks should depend on it rather than on
+// *WrapError. The builders (With, WithCode, Event, ...) are left out: they
+// return *WrapError, so only the concrete type has them.
+type TError interface {
+	Framer
+	Unwrap() error
+	Message() string
+	Code() Code
+	Stack() []Frame
+	Fields() []Field
+	Info() []any
+	Recovery() *Recovery
+	RetryHint() RetryHint
+}
+
+var _ TError = (*WrapError)(nil)
+
+// WrapError is the concrete error type returned by New, Errorf, Wrap and friends.
+// Its builder methods (With, WithCode, ...) return a modified copy and leave
+// the receiver untouched.
+type WrapError struct {
+	msg string
+	// template and args are the format string and arguments msg was built
+	// from, if any.
+	template string
+	args     []any
 	err      error
-	frame    Frame
+	// also holds the %w operands of Wrapf, which match errors.Is and
+	// errors.As without being part of the chain.
+	also  []error
+	frame Frame
+	stack []uintptr
+	// remoteStack is set instead of stack for errors decoded from another
+	// process.
+	remoteStack []Frame
+	fields      []Field
+	// event holds zerolog closures attached through Event. They are opaque,
+	// so Fields only reads them back by running them through zerolog.
 	event    []func(*zerolog.Event) *zerolog.Event
-	code     int
+	code     Code
 	recovery *Recovery
+	retry    RetryHint
+	sentinel *Sentinel
+	// origin is the error a builder method copied this one from.
+	origin *WrapError
 }
 
 type Recovery struct {
 	Suggestion string
 	State      []any
+	// Frame is where the recovery was attached.
+	Frame Frame
 }
 
-func (e *wrapError) Root() error {
+func (e *WrapError) Root() error {
 	return e.err
 }
 
-func (e *wrapError) Frame() Frame {
+func (e *WrapError) Frame() Frame {
 	return e.frame
 }
 
-func (e *wrapError) Recovery() *Recovery {
+// Stack returns the full call stack captured when the error was created, or
+// nil if stack capture was not enabled.
+func (e *WrapError) Stack() []Frame {
+	if e.remoteStack != nil {
+		return e.remoteStack
+	}
+	return resolveStack(e.stack)
+}
+
+func (e *WrapError) Recovery() *Recovery {
 	return e.recovery
 }
 
-func (e *wrapError) WithRecovery(r string, state ...any) *wrapError {
-	e.recovery = &Recovery{r, state}
-	return e
+func (e *WrapError) WithRecovery(r string, state ...any) *WrapError {
+	return e.withRecovery(Caller(1), r, state)
+}
+
+func (me *WrapError) WithRecoveryf(format string, a ...any) *WrapError {
+	return me.withRecovery(Caller(1), fmt.Sprintf(format, a...), nil)
 }
 
-func (me *wrapError) WithRecoveryf(format string, a ...any) *wrapError {
-	return me.WithRecovery(fmt.Sprintf(format, a...))
+func (e *WrapError) withRecovery(frame Frame, r string, state []any) *WrapError {
+	c := e.clone()
+	c.recovery = &Recovery{Suggestion: r, State: state, Frame: frame}
+	return c
 }
 
-func (e *wrapError) Info() []any {
+func (e *WrapError) Info() []any {
 	return []any{e.msg}
 }
 
-func (e *wrapError) Event(gv func(*zerolog.Event) *zerolog.Event) error {
+func (e *WrapError) Event(gv func(*zerolog.Event) *zerolog.Event) *WrapError {
+	c := e.clone()
 	if gv != nil {
-		e.event = append(e.event, gv)
+		c.event = append(c.event, gv)
 	}
-	return e
+	return c
 }
 
-func (e *wrapError) With(name string, value any) *wrapError {
-	e.event = append(e.event, func(ev *zerolog.Event) *zerolog.Event {
-		return ev.Interface(name, value)
-	})
-	return e
+func (e *WrapError) With(name string, value any) *WrapError {
+	c := e.clone()
+	c.fields = append(c.fields, Field{Key: name, Value: value})
+	return c
 }
 
-func (e *wrapError) Error() string {
+func (e *WrapError) Error() string {
 	return InlineChainFormatter(e.Self, e.err)
 }
 
-func (e *wrapError) Code() int {
+func (e *WrapError) Code() Code {
 	return e.code
 }
 
-func (e *wrapError) WithCode(code int) *wrapError {
-	e.code = code
-	return e
+func (e *WrapError) WithCode(code Code) *WrapError {
+	c := e.clone()
+	c.code = code
+	return c
 }
 
-func (e *wrapError) Simple() string {
+func (e *WrapError) Simple() string {
 	return InlineChainFormatter(e.Message, e.err)
 }
 
-func (e *wrapError) Complicated() string {
+func (e *WrapError) Complicated() string {
 	return FullChainFormatter(e.err)
 }
 
-func (e *wrapError) Message() string {
-	if e.code != 0 {
-		return fmt.Sprintf("ERROR%s%s", ColorCode(e.code), ColorBrackets("msg", e.msg))
-	}
-	return fmt.Sprintf("ERROR%s", ColorBrackets("msg", e.msg))
+func (e *WrapError) Message() string {
+	return e.messageWith(DefaultRenderer())
 }
 
-func (e *wrapError) Self() string {
-	return fmt.Sprintf("%s%s", e.Message(), FormatCallerFromFrame(e.Frame()))
+func (e *WrapError) Self() string {
+	return e.selfWith(DefaultRenderer())
 }
 
-func (e *wrapError) DetailedSelf() string {
-	self := e.Self()
-
-	dets := e.Detail()
-
-	if dets != "" {
-		self += fmt.Sprintf("\n\n%s\n\n", dets)
-	}
-
-	return self
+func (e *WrapError) DetailedSelf() string {
+	return e.detailedSelfWith(DefaultRenderer())
 }
 
-func (e *wrapError) Unwrap() error {
+func (e *WrapError) Unwrap() error {
 	return e.err
 }
 
+// clone returns a shallow copy of e for the builder methods, which never
+// modify their receiver so a shared error can be enriched concurrently. The
+// slices are clipped so appending to the copy always reallocates, and the
+// copy remembers e so it still matches it in errors.Is.
+func (e *WrapError) clone() *WrapError {
+	c := *e
+	c.fields = slices.Clip(e.fields)
+	c.event = slices.Clip(e.event)
+	c.origin = e
+	return &c
+}
+
 // Wrap error with message and caller.
-func Wrap(err error, message string) *wrapError {
+func Wrap(err error, message string) *WrapError {
 	return WrapWithCaller(err, message, 1)
 }
 
-// Wrapf wraps error with formatted message and caller.
-func Wrapf(err error, format string, a ...interface{}) *wrapError {
-	return WrapWithCaller(err, fmt.Sprintf(format, a...), 1)
+// Wrapf wraps error with formatted message and caller. err stays the only
+// cause; errors passed with %w are kept in the message and also match
+// errors.Is and errors.As. Without err, Wrapf behaves like Errorf.
+func Wrapf(err error, format string, a ...interface{}) *WrapError {
+	cause, msg, also := formatWrap(err, format, a)
+	we := WrapWithCaller(cause, msg, 1).withTemplate(format, a)
+	we.also = also
+	return we
 }
 
-func WrapWithCaller(err error, message string, frm int) *wrapError {
+func WrapWithCaller(err error, message string, frm int) *WrapError {
+	return wrapWithCaller(err, message, frm+1, false)
+}
+
+// WrapWithFrame wraps err with message using an explicit frame instead of the
+// caller's location, e.g. a RemoteFrame decoded from another process.
+func WrapWithFrame(err error, message string, frame Frame) *WrapError {
+	return &WrapError{msg: message, err: err, frame: frame}
+}
+
+func wrapWithCaller(err error, message string, frm int, withStack bool) *WrapError {
 	frme := Caller(frm + 1)
 
-	return &wrapError{msg: message, err: err, frame: frme, event: []func(*zerolog.Event) *zerolog.Event{}}
+	we := &WrapError{msg: message, err: err, frame: frme}
+
+	if withStack || StackCaptureEnabled() {
+		we.stack = callers(frm + 1)
+	}
+
+	return we
 }
 
-func (c *wrapError) MarshalZerologObject(e *zerolog.Event) (err error) {
-	for _, ev := range c.event {
-		*e = *ev(e)
+// MarshalZerologObject adds the fields of c, redacted for TargetLog, to e.
+func (c *WrapError) MarshalZerologObject(e *zerolog.Event) (err error) {
+	for _, f := range c.FieldsFor(TargetLog) {
+		e.Interface(f.Key, f.Value)
 	}
 	return nil
 }
@@ -231,13 +231,13 @@ func TestUnwrap(t *testing.T) {
 
 func TestOpaque(t *testing.T) {
 	got := fmt.Sprintf("%v", terrors.Wrap(errors.Opaque(errorT{}), "foo"))
-	want := "ERROR[msg=foo][pkg=walteh/terrors_test][file=wrap_test.go:233] 👉 ❌ errorT"
+	want := "ERROR[msg=foo][pkg=walteh/terrors_test][file=wrap_test.go:233] -> errorT"
 	if got != want {
 		t.Errorf("error without Format: got %v; want %v", got, want)
 	}
 
 	got = fmt.Sprintf("%v", terrors.Wrap(errors.Opaque(errorD{}), "foo"))
-	want = "ERROR[msg=foo][pkg=walteh/terrors_test][file=wrap_test.go:239] 👉 ❌ errorD"
+	want = "ERROR[msg=foo][pkg=walteh/terrors_test][file=wrap_test.go:239] -> errorD"
 	if got != want {
 		t.Errorf("error with Format: got %q; want %q", got, want)
 	}
@@ -276,3 +276,47 @@ func (errorUncomparable) Is(target error) bool {
 	_, ok := target.(errorUncomparable)
 	return ok
 }
+
+func TestTErrorContract(t *testing.T) {
+	var terr terrors.TError = terrors.Wrap(errorT{}, "contract").WithCode(12).With("key", "value")
+
+	if terr.Code() != 12 {
+		t.Errorf("Code() = %d, want 12", terr.Code())
+	}
+
+	if terr.Root() != (errorT{}) {
+		t.Errorf("Root() = %v, want errorT", terr.Root())
+	}
+
+	deepest := terrors.GetDeepestTerror(terrors.Wrap(terr, "outer"))
+	if deepest != terr {
+		t.Errorf("GetDeepestTerror() = %v, want %v", deepest, terr)
+	}
+
+	werr, ok := terrors.Into[*terrors.WrapError](terr)
+	if !ok || werr != terr {
+		t.Errorf("Into[*WrapError]() = %v, %v; want %v, true", werr, ok, terr)
+	}
+
+	var mock terrors.TError = mockTError{code: 7}
+	if mock.Code() != 7 {
+		t.Errorf("mock Code() = %d, want 7", mock.Code())
+	}
+}
+
+// mockTError is a TError that is not a *WrapError.
+type mockTError struct{ code terrors.Code }
+
+func (m mockTError) Error() string                { return "mock" }
+func (m mockTError) Root() error                  { return nil }
+func (m mockTError) Frame() terrors.Frame         { return terrors.Frame{} }
+func (m mockTError) Detail() string               { return "mock" }
+func (m mockTError) Simple() string               { return "mock" }
+func (m mockTError) Unwrap() error                { return nil }
+func (m mockTError) Message() string              { return "mock" }
+func (m mockTError) Code() terrors.Code           { return m.code }
+func (m mockTError) Stack() []terrors.Frame       { return nil }
+func (m mockTError) Fields() []terrors.Field      { return nil }
+func (m mockTError) Info() []any                  { return []any{"mock"} }
+func (m mockTError) Recovery() *terrors.Recovery  { return nil }
+func (m mockTError) RetryHint() terrors.RetryHint { return terrors.RetryHint{} }